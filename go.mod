@@ -18,8 +18,14 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/redis/go-redis/v9 v9.7.0
 	golang.org/x/crypto v0.33.0 // indirect
-	golang.org/x/sync v0.11.0 // indirect
+	golang.org/x/sync v0.11.0
 	golang.org/x/text v0.22.0 // indirect
 )
 
-require github.com/golang-cz/devslog v0.0.11
+require (
+	github.com/golang-cz/devslog v0.0.11
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	google.golang.org/protobuf v1.36.9
+)
+
+require github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
@@ -2,19 +2,59 @@ package cache
 
 import (
 	"context"
+	"errors"
+	"iter"
 	"time"
 )
 
-// Client interface to communicate with cache storage.
-type Client interface {
+// Errors returned by Client. Storage errors are wrapped, so they are matched with errors.Is
+// while the message keeps the original error, e.g. "wrong type: WRONGTYPE Operation against...".
+var (
+	// ErrKeyNotFound is returned when a key is not found in a map or other data structure
+	ErrKeyNotFound = errors.New("key not found")
+	// ErrTxFailed is returned when a watched key was changed before the transaction was executed.
+	ErrTxFailed = errors.New("transaction failed")
+	// ErrWrongType is returned when a command is run against a key holding another kind of value.
+	ErrWrongType = errors.New("wrong type")
+	// ErrTimeout is returned when the storage did not respond in time.
+	ErrTimeout = errors.New("timeout")
+	// ErrConnection is returned when the storage is unreachable or the connection was lost.
+	ErrConnection = errors.New("connection error")
+	// ErrReadOnly is returned when a write is sent to a read-only replica, e.g. during failover.
+	ErrReadOnly = errors.New("read only")
+)
+
+// KeepTTL passed as ttl keeps the current TTL of the key, it is the same value as redis.KeepTTL.
+const KeepTTL time.Duration = -1
+
+// Cmdable is the command set shared by Client and Tx.
+//
+// Unless stated otherwise, ttl of the commands works like the expiration of go-redis:
+// positive ttl sets it, zero ttl removes it and KeepTTL leaves it as is.
+type Cmdable interface {
 	// String commands
 	Set(ctx context.Context, key string, value interface{}) error
 	SetEx(ctx context.Context, key string, value interface{}, duration time.Duration) error
+	// SetNX sets the key only if it does not exist and reports whether it was set.
+	SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
+	// SetXX sets the key only if it exists and reports whether it was set.
+	SetXX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
 	Get(ctx context.Context, key string) (string, error)
+	// GetSet sets the key and returns its old value, ErrKeyNotFound if there was none.
+	GetSet(ctx context.Context, key string, value interface{}, ttl time.Duration) (string, error)
+	// GetDel returns the value of the key and deletes it.
+	GetDel(ctx context.Context, key string) (string, error)
+	// GetEx returns the value of the key and updates its TTL.
+	GetEx(ctx context.Context, key string, ttl time.Duration) (string, error)
 	Del(ctx context.Context, key string) error
 	DelAll(ctx context.Context, keys ...string) error
 	Incr(ctx context.Context, key string) error
 	Decr(ctx context.Context, key string) error
+	// IncrBy, IncrByFloat and DecrBy return the new value of the counter. Positive ttl is set
+	// only if the key has no TTL yet, e.g. on the first increment, so a window is not prolonged.
+	IncrBy(ctx context.Context, key string, incr int64, ttl time.Duration) (int64, error)
+	IncrByFloat(ctx context.Context, key string, incr float64, ttl time.Duration) (float64, error)
+	DecrBy(ctx context.Context, key string, decr int64, ttl time.Duration) (int64, error)
 	TTL(ctx context.Context, key string) (time.Duration, error)
 	Expire(ctx context.Context, key string, expiration time.Duration) error
 	ExpireAt(ctx context.Context, key string, tm time.Time) error
@@ -49,7 +89,56 @@ type Client interface {
 	ZPopMin(ctx context.Context, key string, count int64) ([]string, error)
 	ZCount(ctx context.Context, key string) (int64, error)
 	ZRange(ctx context.Context, key string) ([]string, error)
+}
+
+// Client interface to communicate with cache storage.
+type Client interface {
+	Cmdable
+
+	// Pipelines and transactions
+	// Pipeline executes commands queued by fn in a single round trip. It returns the error
+	// of fn or of the first failed command except ErrKeyNotFound.
+	Pipeline(ctx context.Context, fn func(p Pipeliner) error) error
+	// Watch watches the keys and runs fn within an optimistic transaction, see Tx.
+	Watch(ctx context.Context, keys []string, fn func(tx Tx) error) error
+
+	// Scan commands
+	// Scan iterates keys matching the glob pattern, empty match means all keys. Count is a hint
+	// of how many keys are fetched per round trip. A key may be yielded more than once.
+	Scan(ctx context.Context, match string, count int64) iter.Seq2[string, error]
+	HScan(ctx context.Context, key, match string, count int64) iter.Seq2[HashField, error]
+	SScan(ctx context.Context, key, match string, count int64) iter.Seq2[string, error]
+	ZScan(ctx context.Context, key, match string, count int64) iter.Seq2[ScoredMember, error]
+
+	// Pub/Sub commands
+	Publish(ctx context.Context, channel string, message interface{}) error
+	Subscribe(ctx context.Context, channels ...string) (Subscription, error)
+	PSubscribe(ctx context.Context, patterns ...string) (Subscription, error)
 
 	// Connection management
 	Ping(ctx context.Context) error
 }
+
+// Message is a message received from a pub/sub channel.
+type Message struct {
+	// Channel is the channel the message was published to.
+	Channel string
+	// Pattern is the pattern matched by the channel, empty for Subscribe.
+	Pattern string
+	// Payload is the published message.
+	Payload string
+	// Resubscribed marks a message without payload which is received when the channel or the pattern
+	// has been resubscribed after a lost connection. Messages published while disconnected are lost,
+	// so consumers keeping state derived from them, e.g. local caches, should resynchronize it.
+	Resubscribed bool
+}
+
+// Subscription is an active pub/sub subscription. Lost connections are restored and channels
+// are resubscribed transparently, every restored channel or pattern is reported by a message
+// with Resubscribed set. Close is meant to be registered in closer.
+type Subscription interface {
+	// Channel returns channel of received messages which is closed when the subscription is closed.
+	Channel() <-chan Message
+	// Close unsubscribes and closes the message channel.
+	Close() error
+}
@@ -0,0 +1,103 @@
+// Package codec provides encodings used to store typed values in the cache.
+package codec
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrNotProtoMessage is returned by Protobuf codec for values which are not proto.Message.
+var ErrNotProtoMessage = errors.New("codec: value is not a proto.Message")
+
+// Codec encodes values to bytes and decodes them back.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSON is a Codec using encoding/json.
+type JSON struct{}
+
+// Marshal implements Codec interface.
+func (JSON) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal implements Codec interface.
+func (JSON) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// Gob is a Codec using encoding/gob.
+type Gob struct{}
+
+// Marshal implements Codec interface.
+func (Gob) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Unmarshal implements Codec interface.
+func (Gob) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// Msgpack is a Codec using MessagePack encoding.
+type Msgpack struct{}
+
+// Marshal implements Codec interface.
+func (Msgpack) Marshal(v any) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+// Unmarshal implements Codec interface.
+func (Msgpack) Unmarshal(data []byte, v any) error {
+	return msgpack.Unmarshal(data, v)
+}
+
+// Protobuf is a Codec for proto.Message values.
+type Protobuf struct{}
+
+// Marshal implements Codec interface.
+func (Protobuf) Marshal(v any) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrNotProtoMessage, v)
+	}
+
+	return proto.Marshal(m)
+}
+
+// Unmarshal implements Codec interface. v may be a proto.Message or a pointer
+// to a nil proto.Message pointer, which is allocated before decoding.
+func (Protobuf) Unmarshal(data []byte, v any) error {
+	if m, ok := v.(proto.Message); ok {
+		return proto.Unmarshal(data, m)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Pointer {
+		return fmt.Errorf("%w: %T", ErrNotProtoMessage, v)
+	}
+	elem := rv.Elem()
+	if elem.IsNil() {
+		elem.Set(reflect.New(elem.Type().Elem()))
+	}
+	m, ok := elem.Interface().(proto.Message)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrNotProtoMessage, v)
+	}
+
+	return proto.Unmarshal(data, m)
+}
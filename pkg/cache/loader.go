@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/8thgencore/microservice-common/pkg/cache/codec"
+	"golang.org/x/sync/singleflight"
+)
+
+// LoadFunc loads the value from the source of truth when it is missing in the cache.
+type LoadFunc[T any] func(ctx context.Context) (T, error)
+
+// LoaderOption configures Loader.
+type LoaderOption func(o *loaderOptions)
+
+type loaderOptions struct {
+	jitter       float64
+	refreshAhead time.Duration
+}
+
+// WithJitter randomizes TTL of loaded values by up to ±fraction of it, so keys loaded
+// at the same time do not expire at the same time. E.g. 0.1 turns 60s into 54s..66s.
+func WithJitter(fraction float64) LoaderOption {
+	return func(o *loaderOptions) {
+		o.jitter = fraction
+	}
+}
+
+// WithEarlyRefresh makes Loader reload keys in background when their remaining TTL
+// is less than window, so hot keys do not expire under load.
+func WithEarlyRefresh(window time.Duration) LoaderOption {
+	return func(o *loaderOptions) {
+		o.refreshAhead = window
+	}
+}
+
+// Loader implements cache-aside reads: values missing in the cache are loaded and stored with TTL.
+// Concurrent loads of the same key are coalesced into a single call of LoadFunc.
+type Loader[T any] struct {
+	client Client
+	typed  *Typed[T]
+	group  singleflight.Group
+	opts   loaderOptions
+}
+
+// NewLoader creates Loader storing values in the client encoded with the codec.
+func NewLoader[T any](client Client, c codec.Codec, opts ...LoaderOption) *Loader[T] {
+	l := &Loader[T]{
+		client: client,
+		typed:  NewTyped[T](client, c),
+	}
+	for _, opt := range opts {
+		opt(&l.opts)
+	}
+
+	return l
+}
+
+// GetOrLoad returns the cached value of the key or loads it with load and caches it for ttl.
+// Errors of the cache are treated as misses, so the loaded value is returned even if redis is down.
+func (l *Loader[T]) GetOrLoad(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) (T, error) {
+	value, err := l.typed.Get(ctx, key)
+	if err == nil {
+		if l.opts.refreshAhead > 0 && l.expiresSoon(ctx, key) {
+			l.group.DoChan(key, l.loadFunc(context.WithoutCancel(ctx), key, ttl, load))
+		}
+
+		return value, nil
+	}
+
+	// The load is detached from the caller's context, so one cancelled caller
+	// does not fail the others waiting for the same key.
+	ch := l.group.DoChan(key, l.loadFunc(context.WithoutCancel(ctx), key, ttl, load))
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return value, res.Err
+		}
+
+		// Val is nil interface if T is an interface type and load returned nil.
+		v, _ := res.Val.(T)
+
+		return v, nil
+	case <-ctx.Done():
+		return value, ctx.Err()
+	}
+}
+
+// expiresSoon reports whether the remaining TTL of the key is within the early refresh window.
+func (l *Loader[T]) expiresSoon(ctx context.Context, key string) bool {
+	ttl, err := l.client.TTL(ctx, key)
+
+	return err == nil && ttl > 0 && ttl < l.opts.refreshAhead
+}
+
+// loadFunc returns func loading the value and storing it in the cache.
+func (l *Loader[T]) loadFunc(ctx context.Context, key string, ttl time.Duration, load LoadFunc[T]) func() (any, error) {
+	return func() (any, error) {
+		value, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// Failing to cache the value must not fail the read, the client logs the error.
+		_ = l.typed.Set(ctx, key, value, l.jittered(ttl))
+
+		return value, nil
+	}
+}
+
+// jittered returns ttl randomized by the jitter fraction.
+func (l *Loader[T]) jittered(ttl time.Duration) time.Duration {
+	if l.opts.jitter <= 0 || ttl <= 0 {
+		return ttl
+	}
+
+	delta := (rand.Float64()*2 - 1) * l.opts.jitter * float64(ttl) //nolint:gosec
+	if jittered := ttl + time.Duration(delta); jittered > 0 {
+		return jittered
+	}
+
+	return ttl
+}
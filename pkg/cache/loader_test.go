@@ -0,0 +1,55 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/microservice-common/pkg/cache"
+	"github.com/8thgencore/microservice-common/pkg/cache/codec"
+	"github.com/8thgencore/microservice-common/pkg/cache/memory"
+)
+
+func TestLoader(t *testing.T) {
+	ctx := context.Background()
+	client := memory.NewClient()
+	l := cache.NewLoader[string](client, codec.JSON{})
+
+	var loads atomic.Int32
+	load := func(context.Context) (string, error) {
+		loads.Add(1)
+
+		return "value", nil
+	}
+
+	for range 2 {
+		got, err := l.GetOrLoad(ctx, "key", time.Minute, load)
+		if err != nil || got != "value" {
+			t.Fatalf("GetOrLoad = %q, %v, want %q", got, err, "value")
+		}
+	}
+	if n := loads.Load(); n != 1 {
+		t.Errorf("loads = %d, want the cached value to be used", n)
+	}
+
+	errLoad := errors.New("load failed")
+	_, err := l.GetOrLoad(ctx, "other", time.Minute, func(context.Context) (string, error) {
+		return "", errLoad
+	})
+	if !errors.Is(err, errLoad) {
+		t.Errorf("GetOrLoad error = %v, want %v", err, errLoad)
+	}
+}
+
+func TestLoaderNilInterface(t *testing.T) {
+	l := cache.NewLoader[any](memory.NewClient(), codec.JSON{})
+
+	got, err := l.GetOrLoad(context.Background(), "key", time.Minute, func(context.Context) (any, error) {
+		return nil, nil
+	})
+	if err != nil || got != nil {
+		t.Errorf("GetOrLoad = %v, %v, want nil", got, err)
+	}
+}
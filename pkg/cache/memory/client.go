@@ -0,0 +1,940 @@
+// Package memory provides in-process implementation of cache.Client which behaves like
+// the redis one. It is meant for unit tests and local development.
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/8thgencore/microservice-common/pkg/cache"
+)
+
+var (
+	// errWrongType matches cache.ErrWrongType and has the message of the translated redis error.
+	errWrongType = fmt.Errorf("%w: %w", cache.ErrWrongType,
+		errors.New("WRONGTYPE Operation against a key holding the wrong kind of value"))
+	errNotInteger       = errors.New("ERR value is not an integer or out of range")
+	errNotFloat         = errors.New("ERR value is not a valid float")
+	errInvalidExpire    = errors.New("ERR invalid expire time in 'setex' command")
+	errInvalidSetExpire = errors.New("ERR invalid expire time in 'set' command")
+)
+
+// errWrongArgs returns the error redis replies to a variadic command called without values.
+func errWrongArgs(cmd string) error {
+	return fmt.Errorf("ERR wrong number of arguments for '%s' command", cmd)
+}
+
+type kind int
+
+const (
+	kindString kind = iota
+	kindHash
+	kindList
+	kindSet
+	kindZSet
+)
+
+// item is a value stored under a key.
+type item struct {
+	kind      kind
+	str       string
+	hash      map[string]string
+	list      []string
+	set       map[string]struct{}
+	zset      map[string]float64
+	expiresAt time.Time
+}
+
+// empty reports whether the collection item has no elements, such keys are removed like in redis.
+func (it *item) empty() bool {
+	switch it.kind {
+	case kindHash:
+		return len(it.hash) == 0
+	case kindList:
+		return len(it.list) == 0
+	case kindSet:
+		return len(it.set) == 0
+	case kindZSet:
+		return len(it.zset) == 0
+	default:
+		return false
+	}
+}
+
+// Option configures in-memory client.
+type Option func(c *cacheClient)
+
+// WithClock sets the clock used for TTLs, e.g. FakeClock in tests.
+func WithClock(clock Clock) Option {
+	return func(c *cacheClient) {
+		c.clock = clock
+	}
+}
+
+type cacheClient struct {
+	// mu guards items, it is a no-op for the view used to execute pipelines under the lock.
+	mu    sync.Locker
+	items map[string]*item
+	clock Clock
+
+	subsMu sync.Mutex
+	subs   map[*subscription]struct{}
+}
+
+var _ cache.Client = (*cacheClient)(nil)
+
+// NewClient creates in-memory cache client.
+func NewClient(opts ...Option) *cacheClient {
+	c := &cacheClient{
+		mu:    &sync.Mutex{},
+		items: make(map[string]*item),
+		clock: realClock{},
+		subs:  make(map[*subscription]struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// lookup returns the item of the key removing it if expired. Must be called with mu held.
+func (c *cacheClient) lookup(key string) *item {
+	it, ok := c.items[key]
+	if !ok {
+		return nil
+	}
+	if !it.expiresAt.IsZero() && !c.clock.Now().Before(it.expiresAt) {
+		delete(c.items, key)
+		return nil
+	}
+
+	return it
+}
+
+// lookupKind returns the item of the key checking its kind. Must be called with mu held.
+func (c *cacheClient) lookupKind(key string, k kind) (*item, error) {
+	it := c.lookup(key)
+	if it != nil && it.kind != k {
+		return nil, errWrongType
+	}
+
+	return it, nil
+}
+
+// lookupOrCreate returns the item of the key creating an empty one if needed. Must be called with mu held.
+func (c *cacheClient) lookupOrCreate(key string, k kind) (*item, error) {
+	it, err := c.lookupKind(key, k)
+	if err != nil || it != nil {
+		return it, err
+	}
+
+	it = &item{kind: k}
+	switch k {
+	case kindHash:
+		it.hash = make(map[string]string)
+	case kindSet:
+		it.set = make(map[string]struct{})
+	case kindZSet:
+		it.zset = make(map[string]float64)
+	}
+	c.items[key] = it
+
+	return it, nil
+}
+
+// removeEmpty removes the key if its collection became empty. Must be called with mu held.
+func (c *cacheClient) removeEmpty(key string, it *item) {
+	if it.empty() {
+		delete(c.items, key)
+	}
+}
+
+// String commands
+func (c *cacheClient) Set(_ context.Context, key string, value interface{}) error {
+	s, err := format(value)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items[key] = &item{kind: kindString, str: s}
+
+	return nil
+}
+
+func (c *cacheClient) SetEx(_ context.Context, key string, value interface{}, duration time.Duration) error {
+	s, err := format(value)
+	if err != nil {
+		return err
+	}
+	duration = seconds(duration)
+	if duration <= 0 {
+		return errInvalidExpire
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items[key] = &item{kind: kindString, str: s, expiresAt: c.clock.Now().Add(duration)}
+
+	return nil
+}
+
+func (c *cacheClient) SetNX(_ context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	s, err := format(value)
+	if err != nil {
+		return false, err
+	}
+	if ttl < 0 && ttl != cache.KeepTTL {
+		return false, errInvalidSetExpire
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.lookup(key) != nil {
+		return false, nil
+	}
+	c.items[key] = &item{kind: kindString, str: s, expiresAt: c.expiry(ttl, nil)}
+
+	return true, nil
+}
+
+func (c *cacheClient) SetXX(_ context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	s, err := format(value)
+	if err != nil {
+		return false, err
+	}
+	if ttl < 0 && ttl != cache.KeepTTL {
+		return false, errInvalidSetExpire
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	old := c.lookup(key)
+	if old == nil {
+		return false, nil
+	}
+	c.items[key] = &item{kind: kindString, str: s, expiresAt: c.expiry(ttl, old)}
+
+	return true, nil
+}
+
+// expiry returns expiration time of the key set with ttl, KeepTTL keeps the one of the old item.
+// Must be called with mu held.
+func (c *cacheClient) expiry(ttl time.Duration, old *item) time.Time {
+	switch {
+	case ttl == cache.KeepTTL && old != nil:
+		return old.expiresAt
+	case ttl > 0:
+		// go-redis sends ttl in milliseconds rounding sub-millisecond ttl up to 1ms.
+		return c.clock.Now().Add(max(ttl, time.Millisecond).Truncate(time.Millisecond))
+	default:
+		return time.Time{}
+	}
+}
+
+func (c *cacheClient) Get(_ context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupKind(key, kindString)
+	if err != nil {
+		return "", err
+	}
+	if it == nil {
+		return "", cache.ErrKeyNotFound
+	}
+
+	return it.str, nil
+}
+
+func (c *cacheClient) GetSet(_ context.Context, key string, value interface{}, ttl time.Duration) (string, error) {
+	s, err := format(value)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	old, err := c.lookupKind(key, kindString)
+	if err != nil {
+		return "", err
+	}
+	c.items[key] = &item{kind: kindString, str: s, expiresAt: c.expiry(ttl, old)}
+	if old == nil {
+		return "", cache.ErrKeyNotFound
+	}
+
+	return old.str, nil
+}
+
+func (c *cacheClient) GetDel(_ context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupKind(key, kindString)
+	if err != nil {
+		return "", err
+	}
+	if it == nil {
+		return "", cache.ErrKeyNotFound
+	}
+	delete(c.items, key)
+
+	return it.str, nil
+}
+
+func (c *cacheClient) GetEx(_ context.Context, key string, ttl time.Duration) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupKind(key, kindString)
+	if err != nil {
+		return "", err
+	}
+	if it == nil {
+		return "", cache.ErrKeyNotFound
+	}
+	// go-redis sends PERSIST for zero ttl and no option for negative one.
+	if ttl >= 0 {
+		it.expiresAt = c.expiry(ttl, it)
+	}
+
+	return it.str, nil
+}
+
+func (c *cacheClient) Del(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.items, key)
+
+	return nil
+}
+
+func (c *cacheClient) DelAll(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		delete(c.items, key)
+	}
+
+	return nil
+}
+
+func (c *cacheClient) Incr(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.incrBy(key, 1)
+
+	return err
+}
+
+func (c *cacheClient) Decr(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.incrBy(key, -1)
+
+	return err
+}
+
+func (c *cacheClient) IncrBy(_ context.Context, key string, incr int64, ttl time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	val, err := c.incrBy(key, incr)
+	if err != nil {
+		return 0, err
+	}
+	c.counterTTL(key, ttl)
+
+	return val, nil
+}
+
+func (c *cacheClient) IncrByFloat(_ context.Context, key string, incr float64, ttl time.Duration) (float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupKind(key, kindString)
+	if err != nil {
+		return 0, err
+	}
+
+	var cur float64
+	if it != nil {
+		if cur, err = strconv.ParseFloat(it.str, 64); err != nil {
+			return 0, errNotFloat
+		}
+	}
+	val := cur + incr
+	if math.IsNaN(val) || math.IsInf(val, 0) {
+		return 0, errors.New("ERR increment would produce NaN or Infinity")
+	}
+	if it == nil {
+		it = &item{kind: kindString}
+		c.items[key] = it
+	}
+	it.str = strconv.FormatFloat(val, 'f', -1, 64)
+	c.counterTTL(key, ttl)
+
+	return val, nil
+}
+
+func (c *cacheClient) DecrBy(_ context.Context, key string, decr int64, ttl time.Duration) (int64, error) {
+	if decr == math.MinInt64 {
+		return 0, errors.New("ERR decrement would overflow")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	val, err := c.incrBy(key, -decr)
+	if err != nil {
+		return 0, err
+	}
+	c.counterTTL(key, ttl)
+
+	return val, nil
+}
+
+// counterTTL sets positive ttl of the counter if it has none. Must be called with mu held.
+func (c *cacheClient) counterTTL(key string, ttl time.Duration) {
+	if it := c.items[key]; ttl > 0 && it.expiresAt.IsZero() {
+		it.expiresAt = c.expiry(ttl, nil)
+	}
+}
+
+// incrBy increments integer value of the key keeping its TTL. Must be called with mu held.
+func (c *cacheClient) incrBy(key string, incr int64) (int64, error) {
+	it, err := c.lookupKind(key, kindString)
+	if err != nil {
+		return 0, err
+	}
+	if it == nil {
+		it = &item{kind: kindString, str: "0"}
+		c.items[key] = it
+	}
+
+	val, err := addInt(it.str, incr)
+	if err != nil {
+		return 0, err
+	}
+	it.str = strconv.FormatInt(val, 10)
+
+	return val, nil
+}
+
+// addInt parses s as int64 and adds incr to it failing on overflow like redis does.
+func addInt(s string, incr int64) (int64, error) {
+	val, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, errNotInteger
+	}
+	if (incr > 0 && val > math.MaxInt64-incr) || (incr < 0 && val < math.MinInt64-incr) {
+		return 0, errors.New("ERR increment or decrement would overflow")
+	}
+
+	return val + incr, nil
+}
+
+func (c *cacheClient) TTL(_ context.Context, key string) (time.Duration, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// go-redis returns -2 and -1 replies of TTL as is.
+	it := c.lookup(key)
+	if it == nil {
+		return -2, nil
+	}
+	if it.expiresAt.IsZero() {
+		return -1, nil
+	}
+
+	// redis rounds remaining milliseconds to the nearest second.
+	ms := it.expiresAt.Sub(c.clock.Now()).Milliseconds()
+
+	return time.Duration((ms+500)/1000) * time.Second, nil
+}
+
+func (c *cacheClient) Expire(_ context.Context, key string, duration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expireAt(key, c.clock.Now().Add(seconds(duration)))
+
+	return nil
+}
+
+func (c *cacheClient) ExpireAt(_ context.Context, key string, tm time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// go-redis sends EXPIREAT as unix time in seconds.
+	c.expireAt(key, tm.Truncate(time.Second))
+
+	return nil
+}
+
+// seconds rounds the duration like go-redis does for commands taking seconds, e.g. SETEX and EXPIRE:
+// sub-second durations become 1s, longer ones are truncated to whole seconds.
+func seconds(d time.Duration) time.Duration {
+	if d > 0 && d < time.Second {
+		return time.Second
+	}
+
+	return d.Truncate(time.Second)
+}
+
+// expireAt sets expiration time of the key deleting it if the time has passed. Must be called with mu held.
+func (c *cacheClient) expireAt(key string, tm time.Time) {
+	it := c.lookup(key)
+	if it == nil {
+		return
+	}
+	if !tm.After(c.clock.Now()) {
+		delete(c.items, key)
+		return
+	}
+	it.expiresAt = tm
+}
+
+// Hash commands
+func (c *cacheClient) HSet(_ context.Context, key, field string, value interface{}) error {
+	s, err := format(value)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupOrCreate(key, kindHash)
+	if err != nil {
+		return err
+	}
+	it.hash[field] = s
+
+	return nil
+}
+
+func (c *cacheClient) HGet(_ context.Context, key, field string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupKind(key, kindHash)
+	if err != nil {
+		return "", err
+	}
+	if it == nil {
+		return "", cache.ErrKeyNotFound
+	}
+	val, ok := it.hash[field]
+	if !ok {
+		return "", cache.ErrKeyNotFound
+	}
+
+	return val, nil
+}
+
+func (c *cacheClient) HGetAll(_ context.Context, key string) (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupKind(key, kindHash)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string)
+	if it != nil {
+		for field, val := range it.hash {
+			result[field] = val
+		}
+	}
+
+	return result, nil
+}
+
+func (c *cacheClient) HIncrBy(_ context.Context, key, field string, incr int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupOrCreate(key, kindHash)
+	if err != nil {
+		return err
+	}
+
+	cur, ok := it.hash[field]
+	if !ok {
+		cur = "0"
+	}
+	val, err := addInt(cur, incr)
+	if err != nil {
+		c.removeEmpty(key, it)
+		return err
+	}
+	it.hash[field] = strconv.FormatInt(val, 10)
+
+	return nil
+}
+
+// List commands
+func (c *cacheClient) LPush(ctx context.Context, key string, value interface{}) error {
+	_, err := c.LPushAll(ctx, key, value)
+
+	return err
+}
+
+func (c *cacheClient) LPushAll(_ context.Context, key string, values ...interface{}) (int64, error) {
+	if len(values) == 0 {
+		return 0, errWrongArgs("lpush")
+	}
+	vals, err := formatAll(values)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupOrCreate(key, kindList)
+	if err != nil {
+		return 0, err
+	}
+	// LPUSH inserts values one by one at the head, so the last value becomes the first.
+	slices.Reverse(vals)
+	it.list = append(vals, it.list...)
+
+	return int64(len(it.list)), nil
+}
+
+func (c *cacheClient) LPop(_ context.Context, key string) (string, error) {
+	return c.pop(key, true)
+}
+
+func (c *cacheClient) RPop(_ context.Context, key string) (string, error) {
+	return c.pop(key, false)
+}
+
+// pop removes and returns the first or the last element of the list.
+func (c *cacheClient) pop(key string, head bool) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupKind(key, kindList)
+	if err != nil {
+		return "", err
+	}
+	if it == nil {
+		return "", cache.ErrKeyNotFound
+	}
+
+	var val string
+	if head {
+		val, it.list = it.list[0], it.list[1:]
+	} else {
+		val, it.list = it.list[len(it.list)-1], it.list[:len(it.list)-1]
+	}
+	c.removeEmpty(key, it)
+
+	return val, nil
+}
+
+func (c *cacheClient) LTrim(_ context.Context, key string, start, stop int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupKind(key, kindList)
+	if err != nil || it == nil {
+		return err
+	}
+
+	from, to := listRange(int64(len(it.list)), start, stop)
+	it.list = slices.Clone(it.list[from:to])
+	c.removeEmpty(key, it)
+
+	return nil
+}
+
+func (c *cacheClient) LLen(_ context.Context, key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupKind(key, kindList)
+	if err != nil || it == nil {
+		return 0, err
+	}
+
+	return int64(len(it.list)), nil
+}
+
+func (c *cacheClient) LRange(_ context.Context, key string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupKind(key, kindList)
+	if err != nil {
+		return nil, err
+	}
+	if it == nil {
+		return []string{}, nil
+	}
+
+	return slices.Clone(it.list), nil
+}
+
+// listRange converts redis start and stop indexes, which may be negative, to slice bounds.
+func listRange(length, start, stop int64) (from, to int64) {
+	if start < 0 {
+		start = max(length+start, 0)
+	}
+	if stop < 0 {
+		stop = length + stop
+	}
+	stop = min(stop, length-1)
+	if start > stop {
+		return 0, 0
+	}
+
+	return start, stop + 1
+}
+
+// Set commands
+func (c *cacheClient) SAdd(ctx context.Context, key string, value interface{}) (int64, error) {
+	return c.SAddAll(ctx, key, value)
+}
+
+func (c *cacheClient) SAddAll(_ context.Context, key string, values ...interface{}) (int64, error) {
+	if len(values) == 0 {
+		return 0, errWrongArgs("sadd")
+	}
+	vals, err := formatAll(values)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupOrCreate(key, kindSet)
+	if err != nil {
+		return 0, err
+	}
+
+	var added int64
+	for _, v := range vals {
+		if _, ok := it.set[v]; !ok {
+			it.set[v] = struct{}{}
+			added++
+		}
+	}
+
+	return added, nil
+}
+
+func (c *cacheClient) SRem(_ context.Context, key string, value interface{}) (int64, error) {
+	v, err := format(value)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupKind(key, kindSet)
+	if err != nil || it == nil {
+		return 0, err
+	}
+	if _, ok := it.set[v]; !ok {
+		return 0, nil
+	}
+	delete(it.set, v)
+	c.removeEmpty(key, it)
+
+	return 1, nil
+}
+
+func (c *cacheClient) SCard(_ context.Context, key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupKind(key, kindSet)
+	if err != nil || it == nil {
+		return 0, err
+	}
+
+	return int64(len(it.set)), nil
+}
+
+func (c *cacheClient) SIsMember(_ context.Context, key string, value interface{}) (bool, error) {
+	v, err := format(value)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupKind(key, kindSet)
+	if err != nil || it == nil {
+		return false, err
+	}
+	_, ok := it.set[v]
+
+	return ok, nil
+}
+
+func (c *cacheClient) SMembers(_ context.Context, key string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupKind(key, kindSet)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]string, 0)
+	if it != nil {
+		for member := range it.set {
+			members = append(members, member)
+		}
+	}
+	slices.Sort(members)
+
+	return members, nil
+}
+
+// Sorted Set commands
+func (c *cacheClient) ZAdd(ctx context.Context, key string, value interface{}) error {
+	return c.ZAddWithScore(ctx, key, float64(c.clock.Now().UnixMilli()), value)
+}
+
+func (c *cacheClient) ZAddWithScore(_ context.Context, key string, score float64, value interface{}) error {
+	v, err := format(value)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupOrCreate(key, kindZSet)
+	if err != nil {
+		return err
+	}
+	it.zset[v] = score
+
+	return nil
+}
+
+func (c *cacheClient) ZRem(_ context.Context, key string, value interface{}) (int64, error) {
+	v, err := format(value)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupKind(key, kindZSet)
+	if err != nil || it == nil {
+		return 0, err
+	}
+	if _, ok := it.zset[v]; !ok {
+		return 0, nil
+	}
+	delete(it.zset, v)
+	c.removeEmpty(key, it)
+
+	return 1, nil
+}
+
+func (c *cacheClient) ZPopMin(_ context.Context, key string, count int64) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupKind(key, kindZSet)
+	if err != nil {
+		return nil, err
+	}
+	if it == nil || count <= 0 {
+		return nil, nil
+	}
+
+	members := sortedMembers(it.zset)
+	members = members[:min(count, int64(len(members)))]
+	for _, member := range members {
+		delete(it.zset, member)
+	}
+	c.removeEmpty(key, it)
+
+	return members, nil
+}
+
+func (c *cacheClient) ZCount(_ context.Context, key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupKind(key, kindZSet)
+	if err != nil || it == nil {
+		return 0, err
+	}
+
+	return int64(len(it.zset)), nil
+}
+
+func (c *cacheClient) ZRange(_ context.Context, key string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	it, err := c.lookupKind(key, kindZSet)
+	if err != nil {
+		return nil, err
+	}
+	if it == nil {
+		return []string{}, nil
+	}
+
+	return sortedMembers(it.zset), nil
+}
+
+// sortedMembers returns members ordered by score and then lexicographically like redis does.
+func sortedMembers(zset map[string]float64) []string {
+	members := make([]string, 0, len(zset))
+	for member := range zset {
+		members = append(members, member)
+	}
+	slices.SortFunc(members, func(a, b string) int {
+		switch {
+		case zset[a] < zset[b]:
+			return -1
+		case zset[a] > zset[b]:
+			return 1
+		default:
+			return strings.Compare(a, b)
+		}
+	})
+
+	return members
+}
+
+// Connection management
+func (c *cacheClient) Ping(_ context.Context) error {
+	return nil
+}
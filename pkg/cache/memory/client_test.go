@@ -0,0 +1,546 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/microservice-common/pkg/cache"
+	"github.com/8thgencore/microservice-common/pkg/cache/memory"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newClient(t *testing.T) (cache.Client, *memory.FakeClock) {
+	t.Helper()
+
+	clock := memory.NewFakeClock(epoch)
+
+	return memory.NewClient(memory.WithClock(clock)), clock
+}
+
+func TestTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(ctx context.Context, c cache.Client) error
+		advance time.Duration
+		wantTTL time.Duration
+	}{
+		{
+			name:    "missing key",
+			setup:   func(context.Context, cache.Client) error { return nil },
+			wantTTL: -2,
+		},
+		{
+			name:    "no expiration",
+			setup:   func(ctx context.Context, c cache.Client) error { return c.Set(ctx, "k", "v") },
+			wantTTL: -1,
+		},
+		{
+			name:    "setex",
+			setup:   func(ctx context.Context, c cache.Client) error { return c.SetEx(ctx, "k", "v", 10*time.Second) },
+			advance: 4 * time.Second,
+			wantTTL: 6 * time.Second,
+		},
+		{
+			name:    "setex expired",
+			setup:   func(ctx context.Context, c cache.Client) error { return c.SetEx(ctx, "k", "v", 10*time.Second) },
+			advance: 10 * time.Second,
+			wantTTL: -2,
+		},
+		{
+			name: "setex truncated to seconds",
+			setup: func(ctx context.Context, c cache.Client) error {
+				return c.SetEx(ctx, "k", "v", 1500*time.Millisecond)
+			},
+			advance: time.Second,
+			wantTTL: -2,
+		},
+		{
+			name: "expire truncated to seconds",
+			setup: func(ctx context.Context, c cache.Client) error {
+				if err := c.Set(ctx, "k", "v"); err != nil {
+					return err
+				}
+
+				return c.Expire(ctx, "k", 1500*time.Millisecond)
+			},
+			advance: time.Second,
+			wantTTL: -2,
+		},
+		{
+			name: "expire sub-second rounded up",
+			setup: func(ctx context.Context, c cache.Client) error {
+				if err := c.Set(ctx, "k", "v"); err != nil {
+					return err
+				}
+
+				return c.Expire(ctx, "k", 100*time.Millisecond)
+			},
+			advance: 500 * time.Millisecond,
+			// TTL rounds the remaining 500ms to the nearest second.
+			wantTTL: time.Second,
+		},
+		{
+			name: "expire in the past deletes",
+			setup: func(ctx context.Context, c cache.Client) error {
+				if err := c.Set(ctx, "k", "v"); err != nil {
+					return err
+				}
+
+				return c.ExpireAt(ctx, "k", epoch.Add(-time.Second))
+			},
+			wantTTL: -2,
+		},
+		{
+			name: "set removes expiration",
+			setup: func(ctx context.Context, c cache.Client) error {
+				if err := c.SetEx(ctx, "k", "v", time.Minute); err != nil {
+					return err
+				}
+
+				return c.Set(ctx, "k", "v")
+			},
+			wantTTL: -1,
+		},
+		{
+			name: "counter ttl is set once",
+			setup: func(ctx context.Context, c cache.Client) error {
+				if _, err := c.IncrBy(ctx, "k", 1, 10*time.Second); err != nil {
+					return err
+				}
+				_, err := c.IncrBy(ctx, "k", 1, time.Hour)
+
+				return err
+			},
+			wantTTL: 10 * time.Second,
+		},
+		{
+			name: "setxx keeps ttl",
+			setup: func(ctx context.Context, c cache.Client) error {
+				if err := c.SetEx(ctx, "k", "v", 10*time.Second); err != nil {
+					return err
+				}
+				_, err := c.SetXX(ctx, "k", "w", cache.KeepTTL)
+
+				return err
+			},
+			wantTTL: 10 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c, clock := newClient(t)
+
+			if err := tt.setup(ctx, c); err != nil {
+				t.Fatalf("setup: %v", err)
+			}
+			clock.Advance(tt.advance)
+
+			ttl, err := c.TTL(ctx, "k")
+			if err != nil {
+				t.Fatalf("TTL: %v", err)
+			}
+			if ttl != tt.wantTTL {
+				t.Errorf("TTL = %v, want %v", ttl, tt.wantTTL)
+			}
+		})
+	}
+}
+
+func TestWrongType(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		cmd  func(ctx context.Context, c cache.Client, key string) error
+	}{
+		{
+			name: "get list",
+			key:  "list",
+			cmd: func(ctx context.Context, c cache.Client, key string) error {
+				_, err := c.Get(ctx, key)
+
+				return err
+			},
+		},
+		{
+			name: "incr list",
+			key:  "list",
+			cmd: func(ctx context.Context, c cache.Client, key string) error {
+				return c.Incr(ctx, key)
+			},
+		},
+		{
+			name: "hget string",
+			key:  "string",
+			cmd: func(ctx context.Context, c cache.Client, key string) error {
+				_, err := c.HGet(ctx, key, "f")
+
+				return err
+			},
+		},
+		{
+			name: "lpush hash",
+			key:  "hash",
+			cmd: func(ctx context.Context, c cache.Client, key string) error {
+				return c.LPush(ctx, key, "v")
+			},
+		},
+		{
+			name: "sadd zset",
+			key:  "zset",
+			cmd: func(ctx context.Context, c cache.Client, key string) error {
+				_, err := c.SAdd(ctx, key, "v")
+
+				return err
+			},
+		},
+		{
+			name: "zrange set",
+			key:  "set",
+			cmd: func(ctx context.Context, c cache.Client, key string) error {
+				_, err := c.ZRange(ctx, key)
+
+				return err
+			},
+		},
+		{
+			name: "getdel list",
+			key:  "list",
+			cmd: func(ctx context.Context, c cache.Client, key string) error {
+				_, err := c.GetDel(ctx, key)
+
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c, _ := newClient(t)
+
+			seed := []error{
+				c.Set(ctx, "string", "v"),
+				c.HSet(ctx, "hash", "f", "v"),
+				c.LPush(ctx, "list", "v"),
+				c.ZAddWithScore(ctx, "zset", 1, "v"),
+			}
+			_, err := c.SAdd(ctx, "set", "v")
+			if err = errors.Join(append(seed, err)...); err != nil {
+				t.Fatalf("seed: %v", err)
+			}
+
+			if err := tt.cmd(ctx, c, tt.key); !errors.Is(err, cache.ErrWrongType) {
+				t.Errorf("error = %v, want %v", err, cache.ErrWrongType)
+			}
+		})
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  func(ctx context.Context, c cache.Client) error
+	}{
+		{
+			name: "get",
+			cmd: func(ctx context.Context, c cache.Client) error {
+				_, err := c.Get(ctx, "missing")
+
+				return err
+			},
+		},
+		{
+			name: "hget missing field",
+			cmd: func(ctx context.Context, c cache.Client) error {
+				if err := c.HSet(ctx, "hash", "f", "v"); err != nil {
+					return err
+				}
+				_, err := c.HGet(ctx, "hash", "missing")
+
+				return err
+			},
+		},
+		{
+			name: "lpop empty list",
+			cmd: func(ctx context.Context, c cache.Client) error {
+				if err := c.LPush(ctx, "list", "v"); err != nil {
+					return err
+				}
+				if _, err := c.LPop(ctx, "list"); err != nil {
+					return err
+				}
+				_, err := c.LPop(ctx, "list")
+
+				return err
+			},
+		},
+		{
+			name: "getset missing",
+			cmd: func(ctx context.Context, c cache.Client) error {
+				_, err := c.GetSet(ctx, "missing", "v", 0)
+
+				return err
+			},
+		},
+		{
+			name: "getdel missing",
+			cmd: func(ctx context.Context, c cache.Client) error {
+				_, err := c.GetDel(ctx, "missing")
+
+				return err
+			},
+		},
+		{
+			name: "getex missing",
+			cmd: func(ctx context.Context, c cache.Client) error {
+				_, err := c.GetEx(ctx, "missing", time.Minute)
+
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newClient(t)
+
+			if err := tt.cmd(context.Background(), c); !errors.Is(err, cache.ErrKeyNotFound) {
+				t.Errorf("error = %v, want %v", err, cache.ErrKeyNotFound)
+			}
+		})
+	}
+}
+
+func TestEmptyCollections(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newClient(t)
+
+	if got, err := c.LRange(ctx, "missing"); err != nil || len(got) != 0 {
+		t.Errorf("LRange = %v, %v, want empty", got, err)
+	}
+	if got, err := c.SMembers(ctx, "missing"); err != nil || len(got) != 0 {
+		t.Errorf("SMembers = %v, %v, want empty", got, err)
+	}
+	if got, err := c.ZRange(ctx, "missing"); err != nil || len(got) != 0 {
+		t.Errorf("ZRange = %v, %v, want empty", got, err)
+	}
+
+	// A collection is removed with its last element like in redis.
+	if err := c.LPush(ctx, "list", "v"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.RPop(ctx, "list"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set(ctx, "list", "v"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := c.Get(ctx, "list"); err != nil || got != "v" {
+		t.Errorf("Get = %q, %v, want %q", got, err, "v")
+	}
+}
+
+func TestNoValues(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newClient(t)
+
+	if _, err := c.LPushAll(ctx, "list"); err == nil {
+		t.Error("LPushAll without values succeeded")
+	}
+	if _, err := c.SAddAll(ctx, "set"); err == nil {
+		t.Error("SAddAll without values succeeded")
+	}
+
+	// No empty collections are left behind.
+	for _, key := range []string{"list", "set"} {
+		if ttl, _ := c.TTL(ctx, key); ttl != -2 {
+			t.Errorf("TTL(%s) = %v, want -2", key, ttl)
+		}
+	}
+	for key, err := range c.Scan(ctx, "", 0) {
+		t.Errorf("Scan yielded %q, %v", key, err)
+	}
+}
+
+func TestOrdering(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("list", func(t *testing.T) {
+		c, _ := newClient(t)
+
+		if _, err := c.LPushAll(ctx, "list", "a", "b", "c"); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.LPush(ctx, "list", "d"); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := c.LRange(ctx, "list"); !slices.Equal(got, []string{"d", "c", "b", "a"}) {
+			t.Errorf("LRange = %v", got)
+		}
+		if got, _ := c.RPop(ctx, "list"); got != "a" {
+			t.Errorf("RPop = %q, want %q", got, "a")
+		}
+		if got, _ := c.LPop(ctx, "list"); got != "d" {
+			t.Errorf("LPop = %q, want %q", got, "d")
+		}
+		if err := c.LTrim(ctx, "list", 0, -2); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := c.LRange(ctx, "list"); !slices.Equal(got, []string{"c"}) {
+			t.Errorf("LRange after LTrim = %v", got)
+		}
+	})
+
+	t.Run("zset", func(t *testing.T) {
+		c, _ := newClient(t)
+
+		for _, m := range []struct {
+			score  float64
+			member string
+		}{{3, "c"}, {1, "b"}, {1, "a"}, {2, "d"}} {
+			if err := c.ZAddWithScore(ctx, "zset", m.score, m.member); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// Members with equal scores are ordered lexicographically.
+		if got, _ := c.ZRange(ctx, "zset"); !slices.Equal(got, []string{"a", "b", "d", "c"}) {
+			t.Errorf("ZRange = %v", got)
+		}
+		if got, _ := c.ZPopMin(ctx, "zset", 2); !slices.Equal(got, []string{"a", "b"}) {
+			t.Errorf("ZPopMin = %v", got)
+		}
+		if got, _ := c.ZCount(ctx, "zset"); got != 2 {
+			t.Errorf("ZCount = %d, want 2", got)
+		}
+	})
+}
+
+func TestPipeline(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newClient(t)
+
+	var (
+		incr    *cache.Result[int64]
+		missing *cache.Result[string]
+		get     *cache.Result[string]
+	)
+	err := c.Pipeline(ctx, func(p cache.Pipeliner) error {
+		p.Set(ctx, "k", "v")
+		incr = p.IncrBy(ctx, "n", 5, 0)
+		missing = p.Get(ctx, "missing")
+		get = p.Get(ctx, "k")
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Pipeline: %v", err)
+	}
+	if got, err := incr.Result(); err != nil || got != 5 {
+		t.Errorf("IncrBy = %d, %v, want 5", got, err)
+	}
+	if err := missing.Err(); !errors.Is(err, cache.ErrKeyNotFound) {
+		t.Errorf("Get missing error = %v, want %v", err, cache.ErrKeyNotFound)
+	}
+	if got := get.Val(); got != "v" {
+		t.Errorf("Get = %q, want %q", got, "v")
+	}
+
+	// The first failed command is returned, the others are still executed.
+	err = c.Pipeline(ctx, func(p cache.Pipeliner) error {
+		p.LPush(ctx, "k", "v")
+		p.Set(ctx, "after", "v")
+
+		return nil
+	})
+	if !errors.Is(err, cache.ErrWrongType) {
+		t.Errorf("Pipeline error = %v, want %v", err, cache.ErrWrongType)
+	}
+	if _, err := c.Get(ctx, "after"); err != nil {
+		t.Errorf("Get after failed command: %v", err)
+	}
+
+	// Nothing is executed if fn fails.
+	errAbort := errors.New("abort")
+	err = c.Pipeline(ctx, func(p cache.Pipeliner) error {
+		p.Set(ctx, "aborted", "v")
+
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Errorf("Pipeline error = %v, want %v", err, errAbort)
+	}
+	if _, err := c.Get(ctx, "aborted"); !errors.Is(err, cache.ErrKeyNotFound) {
+		t.Errorf("Get aborted error = %v, want %v", err, cache.ErrKeyNotFound)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(ctx context.Context, c cache.Client) error
+		wantErr error
+	}{
+		{
+			name:   "unchanged",
+			change: func(context.Context, cache.Client) error { return nil },
+		},
+		{
+			name:    "changed",
+			change:  func(ctx context.Context, c cache.Client) error { return c.Set(ctx, "k", "2") },
+			wantErr: cache.ErrTxFailed,
+		},
+		{
+			name:    "deleted",
+			change:  func(ctx context.Context, c cache.Client) error { return c.Del(ctx, "k") },
+			wantErr: cache.ErrTxFailed,
+		},
+		{
+			name:   "rewritten with the same value",
+			change: func(ctx context.Context, c cache.Client) error { return c.Set(ctx, "k", "1") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c, _ := newClient(t)
+
+			if err := c.Set(ctx, "k", "1"); err != nil {
+				t.Fatal(err)
+			}
+
+			err := c.Watch(ctx, []string{"k"}, func(tx cache.Tx) error {
+				val, err := tx.Get(ctx, "k")
+				if err != nil {
+					return err
+				}
+				if err := tt.change(ctx, c); err != nil {
+					return err
+				}
+
+				return tx.Exec(ctx, func(p cache.Pipeliner) error {
+					p.Set(ctx, "k", val+"0")
+
+					return nil
+				})
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Watch error = %v, want %v", err, tt.wantErr)
+			}
+
+			got, _ := c.Get(ctx, "k")
+			if tt.wantErr == nil && got != "10" {
+				t.Errorf("Get = %q, want %q", got, "10")
+			}
+			if tt.wantErr != nil && got == "10" {
+				t.Errorf("transaction was applied")
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/8thgencore/microservice-common/pkg/cache"
+)
+
+// Pipelines and transactions
+func (c *cacheClient) Pipeline(_ context.Context, fn func(p cache.Pipeliner) error) error {
+	p := &pipeline{client: c}
+	if err := fn(p); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.exec(p)
+}
+
+// Watch detects changes of the watched keys by comparing their values, so writes
+// which leave a key as it was do not fail the transaction unlike in redis.
+func (c *cacheClient) Watch(_ context.Context, keys []string, fn func(tx cache.Tx) error) error {
+	c.mu.Lock()
+	watched := make(map[string]*item, len(keys))
+	for _, key := range keys {
+		watched[key] = c.lookup(key).clone()
+	}
+	c.mu.Unlock()
+
+	return fn(&tx{cacheClient: c, watched: watched})
+}
+
+// tx runs commands immediately, Exec applies queued ones under the lock if the watched keys are unchanged.
+type tx struct {
+	*cacheClient
+	watched map[string]*item
+}
+
+func (t *tx) Exec(_ context.Context, fn func(p cache.Pipeliner) error) error {
+	p := &pipeline{client: t.cacheClient}
+	if err := fn(p); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Like EXEC, the keys are unwatched after the first execution.
+	watched := t.watched
+	t.watched = nil
+	for key, it := range watched {
+		if !reflect.DeepEqual(t.lookup(key), it) {
+			return cache.ErrTxFailed
+		}
+	}
+
+	return t.exec(p)
+}
+
+// exec runs queued commands returning the first error except not found. Must be called with mu held.
+func (c *cacheClient) exec(p *pipeline) error {
+	view := &cacheClient{mu: noLock{}, items: c.items, clock: c.clock}
+
+	var first error
+	for _, op := range p.ops {
+		if err := op(view); err != nil && first == nil && !errors.Is(err, cache.ErrKeyNotFound) {
+			first = err
+		}
+	}
+
+	return first
+}
+
+// noLock is a no-op sync.Locker.
+type noLock struct{}
+
+func (noLock) Lock()   {}
+func (noLock) Unlock() {}
+
+// clone returns a deep copy of the item, nil for nil.
+func (it *item) clone() *item {
+	if it == nil {
+		return nil
+	}
+
+	cp := *it
+	cp.hash = maps.Clone(it.hash)
+	cp.list = slices.Clone(it.list)
+	cp.set = maps.Clone(it.set)
+	cp.zset = maps.Clone(it.zset)
+
+	return &cp
+}
+
+// pipeline queues commands which are run against the view of the client holding its lock.
+type pipeline struct {
+	client *cacheClient
+	ops    []func(c *cacheClient) error
+}
+
+// queue queues the command returning its result.
+func queue[T any](p *pipeline, fn func(c *cacheClient) (T, error)) *cache.Result[T] {
+	var (
+		val T
+		err error
+	)
+	p.ops = append(p.ops, func(c *cacheClient) error {
+		val, err = fn(c)
+
+		return err
+	})
+
+	return cache.NewResult(func() (T, error) {
+		return val, err
+	})
+}
+
+// status queues the command which returns only an error.
+func status(p *pipeline, fn func(c *cacheClient) error) *cache.Result[struct{}] {
+	return queue(p, func(c *cacheClient) (struct{}, error) {
+		return struct{}{}, fn(c)
+	})
+}
+
+// String commands
+func (p *pipeline) Set(ctx context.Context, key string, value interface{}) *cache.Result[struct{}] {
+	return status(p, func(c *cacheClient) error { return c.Set(ctx, key, value) })
+}
+
+func (p *pipeline) SetEx(
+	ctx context.Context, key string, value interface{}, duration time.Duration,
+) *cache.Result[struct{}] {
+	return status(p, func(c *cacheClient) error { return c.SetEx(ctx, key, value, duration) })
+}
+
+func (p *pipeline) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) *cache.Result[bool] {
+	return queue(p, func(c *cacheClient) (bool, error) { return c.SetNX(ctx, key, value, ttl) })
+}
+
+func (p *pipeline) SetXX(ctx context.Context, key string, value interface{}, ttl time.Duration) *cache.Result[bool] {
+	return queue(p, func(c *cacheClient) (bool, error) { return c.SetXX(ctx, key, value, ttl) })
+}
+
+func (p *pipeline) Get(ctx context.Context, key string) *cache.Result[string] {
+	return queue(p, func(c *cacheClient) (string, error) { return c.Get(ctx, key) })
+}
+
+func (p *pipeline) GetSet(
+	ctx context.Context, key string, value interface{}, ttl time.Duration,
+) *cache.Result[string] {
+	return queue(p, func(c *cacheClient) (string, error) { return c.GetSet(ctx, key, value, ttl) })
+}
+
+func (p *pipeline) GetDel(ctx context.Context, key string) *cache.Result[string] {
+	return queue(p, func(c *cacheClient) (string, error) { return c.GetDel(ctx, key) })
+}
+
+func (p *pipeline) GetEx(ctx context.Context, key string, ttl time.Duration) *cache.Result[string] {
+	return queue(p, func(c *cacheClient) (string, error) { return c.GetEx(ctx, key, ttl) })
+}
+
+func (p *pipeline) Del(ctx context.Context, key string) *cache.Result[struct{}] {
+	return status(p, func(c *cacheClient) error { return c.Del(ctx, key) })
+}
+
+func (p *pipeline) DelAll(ctx context.Context, keys ...string) *cache.Result[struct{}] {
+	return status(p, func(c *cacheClient) error { return c.DelAll(ctx, keys...) })
+}
+
+func (p *pipeline) Incr(ctx context.Context, key string) *cache.Result[struct{}] {
+	return status(p, func(c *cacheClient) error { return c.Incr(ctx, key) })
+}
+
+func (p *pipeline) Decr(ctx context.Context, key string) *cache.Result[struct{}] {
+	return status(p, func(c *cacheClient) error { return c.Decr(ctx, key) })
+}
+
+func (p *pipeline) IncrBy(ctx context.Context, key string, incr int64, ttl time.Duration) *cache.Result[int64] {
+	return queue(p, func(c *cacheClient) (int64, error) { return c.IncrBy(ctx, key, incr, ttl) })
+}
+
+func (p *pipeline) IncrByFloat(
+	ctx context.Context, key string, incr float64, ttl time.Duration,
+) *cache.Result[float64] {
+	return queue(p, func(c *cacheClient) (float64, error) { return c.IncrByFloat(ctx, key, incr, ttl) })
+}
+
+func (p *pipeline) DecrBy(ctx context.Context, key string, decr int64, ttl time.Duration) *cache.Result[int64] {
+	return queue(p, func(c *cacheClient) (int64, error) { return c.DecrBy(ctx, key, decr, ttl) })
+}
+
+func (p *pipeline) TTL(ctx context.Context, key string) *cache.Result[time.Duration] {
+	return queue(p, func(c *cacheClient) (time.Duration, error) { return c.TTL(ctx, key) })
+}
+
+func (p *pipeline) Expire(ctx context.Context, key string, duration time.Duration) *cache.Result[struct{}] {
+	return status(p, func(c *cacheClient) error { return c.Expire(ctx, key, duration) })
+}
+
+func (p *pipeline) ExpireAt(ctx context.Context, key string, tm time.Time) *cache.Result[struct{}] {
+	return status(p, func(c *cacheClient) error { return c.ExpireAt(ctx, key, tm) })
+}
+
+// Hash commands
+func (p *pipeline) HSet(ctx context.Context, key, field string, value interface{}) *cache.Result[struct{}] {
+	return status(p, func(c *cacheClient) error { return c.HSet(ctx, key, field, value) })
+}
+
+func (p *pipeline) HGet(ctx context.Context, key, field string) *cache.Result[string] {
+	return queue(p, func(c *cacheClient) (string, error) { return c.HGet(ctx, key, field) })
+}
+
+func (p *pipeline) HGetAll(ctx context.Context, key string) *cache.Result[map[string]string] {
+	return queue(p, func(c *cacheClient) (map[string]string, error) { return c.HGetAll(ctx, key) })
+}
+
+func (p *pipeline) HIncrBy(ctx context.Context, key, field string, incr int64) *cache.Result[struct{}] {
+	return status(p, func(c *cacheClient) error { return c.HIncrBy(ctx, key, field, incr) })
+}
+
+// List commands
+func (p *pipeline) LPush(ctx context.Context, key string, value interface{}) *cache.Result[struct{}] {
+	return status(p, func(c *cacheClient) error { return c.LPush(ctx, key, value) })
+}
+
+func (p *pipeline) LPushAll(ctx context.Context, key string, values ...interface{}) *cache.Result[int64] {
+	return queue(p, func(c *cacheClient) (int64, error) { return c.LPushAll(ctx, key, values...) })
+}
+
+func (p *pipeline) LPop(ctx context.Context, key string) *cache.Result[string] {
+	return queue(p, func(c *cacheClient) (string, error) { return c.LPop(ctx, key) })
+}
+
+func (p *pipeline) RPop(ctx context.Context, key string) *cache.Result[string] {
+	return queue(p, func(c *cacheClient) (string, error) { return c.RPop(ctx, key) })
+}
+
+func (p *pipeline) LTrim(ctx context.Context, key string, start, stop int64) *cache.Result[struct{}] {
+	return status(p, func(c *cacheClient) error { return c.LTrim(ctx, key, start, stop) })
+}
+
+func (p *pipeline) LLen(ctx context.Context, key string) *cache.Result[int64] {
+	return queue(p, func(c *cacheClient) (int64, error) { return c.LLen(ctx, key) })
+}
+
+func (p *pipeline) LRange(ctx context.Context, key string) *cache.Result[[]string] {
+	return queue(p, func(c *cacheClient) ([]string, error) { return c.LRange(ctx, key) })
+}
+
+// Set commands
+func (p *pipeline) SAdd(ctx context.Context, key string, value interface{}) *cache.Result[int64] {
+	return queue(p, func(c *cacheClient) (int64, error) { return c.SAdd(ctx, key, value) })
+}
+
+func (p *pipeline) SAddAll(ctx context.Context, key string, values ...interface{}) *cache.Result[int64] {
+	return queue(p, func(c *cacheClient) (int64, error) { return c.SAddAll(ctx, key, values...) })
+}
+
+func (p *pipeline) SRem(ctx context.Context, key string, value interface{}) *cache.Result[int64] {
+	return queue(p, func(c *cacheClient) (int64, error) { return c.SRem(ctx, key, value) })
+}
+
+func (p *pipeline) SCard(ctx context.Context, key string) *cache.Result[int64] {
+	return queue(p, func(c *cacheClient) (int64, error) { return c.SCard(ctx, key) })
+}
+
+func (p *pipeline) SIsMember(ctx context.Context, key string, value interface{}) *cache.Result[bool] {
+	return queue(p, func(c *cacheClient) (bool, error) { return c.SIsMember(ctx, key, value) })
+}
+
+func (p *pipeline) SMembers(ctx context.Context, key string) *cache.Result[[]string] {
+	return queue(p, func(c *cacheClient) ([]string, error) { return c.SMembers(ctx, key) })
+}
+
+// Sorted Set commands
+func (p *pipeline) ZAdd(ctx context.Context, key string, value interface{}) *cache.Result[struct{}] {
+	return status(p, func(c *cacheClient) error { return c.ZAdd(ctx, key, value) })
+}
+
+func (p *pipeline) ZAddWithScore(
+	ctx context.Context, key string, score float64, value interface{},
+) *cache.Result[struct{}] {
+	return status(p, func(c *cacheClient) error { return c.ZAddWithScore(ctx, key, score, value) })
+}
+
+func (p *pipeline) ZRem(ctx context.Context, key string, value interface{}) *cache.Result[int64] {
+	return queue(p, func(c *cacheClient) (int64, error) { return c.ZRem(ctx, key, value) })
+}
+
+func (p *pipeline) ZPopMin(ctx context.Context, key string, count int64) *cache.Result[[]string] {
+	return queue(p, func(c *cacheClient) ([]string, error) { return c.ZPopMin(ctx, key, count) })
+}
+
+func (p *pipeline) ZCount(ctx context.Context, key string) *cache.Result[int64] {
+	return queue(p, func(c *cacheClient) (int64, error) { return c.ZCount(ctx, key) })
+}
+
+func (p *pipeline) ZRange(ctx context.Context, key string) *cache.Result[[]string] {
+	return queue(p, func(c *cacheClient) ([]string, error) { return c.ZRange(ctx, key) })
+}
+
+// Pub/Sub commands
+func (p *pipeline) Publish(ctx context.Context, channel string, message interface{}) *cache.Result[struct{}] {
+	// Publish does not touch items, so it is run by the client itself.
+	return status(p, func(*cacheClient) error { return p.client.Publish(ctx, channel, message) })
+}
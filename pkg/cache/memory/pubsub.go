@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/8thgencore/microservice-common/pkg/cache"
+)
+
+// subscriptionBuffer is the number of messages buffered per subscription,
+// messages published to a full subscription are dropped like for slow redis clients.
+const subscriptionBuffer = 100
+
+// subscription is an in-memory pub/sub subscription.
+type subscription struct {
+	client   *cacheClient
+	channels []string
+	patterns []string
+	ch       chan cache.Message
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// Pub/Sub commands
+func (c *cacheClient) Publish(_ context.Context, channel string, message interface{}) error {
+	payload, err := format(message)
+	if err != nil {
+		return err
+	}
+
+	c.subsMu.Lock()
+	subs := make([]*subscription, 0, len(c.subs))
+	for s := range c.subs {
+		subs = append(subs, s)
+	}
+	c.subsMu.Unlock()
+
+	for _, s := range subs {
+		s.deliver(channel, payload)
+	}
+
+	return nil
+}
+
+func (c *cacheClient) Subscribe(_ context.Context, channels ...string) (cache.Subscription, error) {
+	return c.subscribe(&subscription{channels: channels}), nil
+}
+
+func (c *cacheClient) PSubscribe(_ context.Context, patterns ...string) (cache.Subscription, error) {
+	return c.subscribe(&subscription{patterns: patterns}), nil
+}
+
+func (c *cacheClient) subscribe(s *subscription) *subscription {
+	s.client = c
+	s.ch = make(chan cache.Message, subscriptionBuffer)
+
+	c.subsMu.Lock()
+	c.subs[s] = struct{}{}
+	c.subsMu.Unlock()
+
+	return s
+}
+
+// deliver sends the message to the subscription if it matches one of its channels or patterns.
+func (s *subscription) deliver(channel, payload string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	for _, ch := range s.channels {
+		if ch == channel {
+			s.send(cache.Message{Channel: channel, Payload: payload})
+		}
+	}
+	for _, pattern := range s.patterns {
+		if match(pattern, channel) {
+			s.send(cache.Message{Channel: channel, Pattern: pattern, Payload: payload})
+		}
+	}
+}
+
+func (s *subscription) send(msg cache.Message) {
+	select {
+	case s.ch <- msg:
+	default:
+	}
+}
+
+func (s *subscription) Channel() <-chan cache.Message {
+	return s.ch
+}
+
+func (s *subscription) Close() error {
+	s.client.subsMu.Lock()
+	delete(s.client.subs, s)
+	s.client.subsMu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+
+	return nil
+}
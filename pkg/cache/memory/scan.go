@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"context"
+	"iter"
+	"slices"
+	"strings"
+
+	"github.com/8thgencore/microservice-common/pkg/cache"
+)
+
+// Scan commands. Matching elements are collected under the lock and yielded without it,
+// so the loop body may use the client. The count hint is ignored.
+func (c *cacheClient) Scan(_ context.Context, pattern string, _ int64) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		c.mu.Lock()
+		keys := make([]string, 0)
+		for key := range c.items {
+			if c.lookup(key) != nil && matchAll(pattern, key) {
+				keys = append(keys, key)
+			}
+		}
+		c.mu.Unlock()
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			if !yield(key, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (c *cacheClient) HScan(_ context.Context, key, pattern string, _ int64) iter.Seq2[cache.HashField, error] {
+	return func(yield func(cache.HashField, error) bool) {
+		c.mu.Lock()
+		it, err := c.lookupKind(key, kindHash)
+		fields := make([]cache.HashField, 0)
+		if it != nil {
+			for field, val := range it.hash {
+				if matchAll(pattern, field) {
+					fields = append(fields, cache.HashField{Field: field, Value: val})
+				}
+			}
+		}
+		c.mu.Unlock()
+		if err != nil {
+			yield(cache.HashField{}, err)
+			return
+		}
+		slices.SortFunc(fields, func(a, b cache.HashField) int {
+			return strings.Compare(a.Field, b.Field)
+		})
+
+		for _, field := range fields {
+			if !yield(field, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (c *cacheClient) SScan(_ context.Context, key, pattern string, _ int64) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		c.mu.Lock()
+		it, err := c.lookupKind(key, kindSet)
+		members := make([]string, 0)
+		if it != nil {
+			for member := range it.set {
+				if matchAll(pattern, member) {
+					members = append(members, member)
+				}
+			}
+		}
+		c.mu.Unlock()
+		if err != nil {
+			yield("", err)
+			return
+		}
+		slices.Sort(members)
+
+		for _, member := range members {
+			if !yield(member, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (c *cacheClient) ZScan(_ context.Context, key, pattern string, _ int64) iter.Seq2[cache.ScoredMember, error] {
+	return func(yield func(cache.ScoredMember, error) bool) {
+		c.mu.Lock()
+		it, err := c.lookupKind(key, kindZSet)
+		members := make([]cache.ScoredMember, 0)
+		if it != nil {
+			for _, member := range sortedMembers(it.zset) {
+				if matchAll(pattern, member) {
+					members = append(members, cache.ScoredMember{Member: member, Score: it.zset[member]})
+				}
+			}
+		}
+		c.mu.Unlock()
+		if err != nil {
+			yield(cache.ScoredMember{}, err)
+			return
+		}
+
+		for _, member := range members {
+			if !yield(member, nil) {
+				return
+			}
+		}
+	}
+}
+
+// matchAll reports whether s matches the glob pattern, empty pattern matches everything like omitted MATCH.
+func matchAll(pattern, s string) bool {
+	return pattern == "" || match(pattern, s)
+}
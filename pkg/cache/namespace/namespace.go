@@ -0,0 +1,260 @@
+// Package namespace provides cache.Client decorator which prefixes keys with a service
+// namespace and a schema version, e.g. "orders:v2:user:42". Services sharing one redis
+// do not collide, and bumping the version makes entries of the old shape invisible
+// without flushing redis; they expire by their TTLs.
+package namespace
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/8thgencore/microservice-common/pkg/cache"
+)
+
+type cacheClient struct {
+	cmdable
+	client cache.Client
+	// channelPrefix prefixes pub/sub channels. It has no version, so instances running
+	// different versions during a rolling update still receive each other's messages.
+	channelPrefix string
+}
+
+var _ cache.Client = (*cacheClient)(nil)
+
+// New creates client prefixing all keys of the client with "<namespace>:v<version>:"
+// and pub/sub channels with "<namespace>:".
+func New(client cache.Client, namespace string, version int) *cacheClient {
+	return &cacheClient{
+		cmdable: cmdable{
+			cmd:    client,
+			prefix: namespace + ":v" + strconv.Itoa(version) + ":",
+		},
+		client:        client,
+		channelPrefix: namespace + ":",
+	}
+}
+
+// Pipelines and transactions
+func (c *cacheClient) Pipeline(ctx context.Context, fn func(p cache.Pipeliner) error) error {
+	return c.client.Pipeline(ctx, func(p cache.Pipeliner) error {
+		return fn(&pipeline{pipe: p, prefix: c.prefix, channelPrefix: c.channelPrefix})
+	})
+}
+
+func (c *cacheClient) Watch(ctx context.Context, keys []string, fn func(tx cache.Tx) error) error {
+	return c.client.Watch(ctx, c.keys(keys), func(t cache.Tx) error {
+		return fn(&tx{cmdable: cmdable{cmd: t, prefix: c.prefix}, tx: t, channelPrefix: c.channelPrefix})
+	})
+}
+
+// tx prefixes keys of the transaction.
+type tx struct {
+	cmdable
+	tx            cache.Tx
+	channelPrefix string
+}
+
+func (t *tx) Exec(ctx context.Context, fn func(p cache.Pipeliner) error) error {
+	return t.tx.Exec(ctx, func(p cache.Pipeliner) error {
+		return fn(&pipeline{pipe: p, prefix: t.prefix, channelPrefix: t.channelPrefix})
+	})
+}
+
+// Connection management
+func (c *cacheClient) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx)
+}
+
+// cmdable prefixes keys of the commands.
+type cmdable struct {
+	cmd    cache.Cmdable
+	prefix string
+}
+
+func (c *cmdable) key(key string) string {
+	return c.prefix + key
+}
+
+func (c *cmdable) keys(keys []string) []string {
+	return withPrefix(c.prefix, keys)
+}
+
+// withPrefix returns the names prefixed with prefix.
+func withPrefix(prefix string, names []string) []string {
+	prefixed := make([]string, 0, len(names))
+	for _, name := range names {
+		prefixed = append(prefixed, prefix+name)
+	}
+
+	return prefixed
+}
+
+// String commands
+func (c *cmdable) Set(ctx context.Context, key string, value interface{}) error {
+	return c.cmd.Set(ctx, c.key(key), value)
+}
+
+func (c *cmdable) SetEx(ctx context.Context, key string, value interface{}, duration time.Duration) error {
+	return c.cmd.SetEx(ctx, c.key(key), value, duration)
+}
+
+func (c *cmdable) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return c.cmd.SetNX(ctx, c.key(key), value, ttl)
+}
+
+func (c *cmdable) SetXX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return c.cmd.SetXX(ctx, c.key(key), value, ttl)
+}
+
+func (c *cmdable) Get(ctx context.Context, key string) (string, error) {
+	return c.cmd.Get(ctx, c.key(key))
+}
+
+func (c *cmdable) GetSet(ctx context.Context, key string, value interface{}, ttl time.Duration) (string, error) {
+	return c.cmd.GetSet(ctx, c.key(key), value, ttl)
+}
+
+func (c *cmdable) GetDel(ctx context.Context, key string) (string, error) {
+	return c.cmd.GetDel(ctx, c.key(key))
+}
+
+func (c *cmdable) GetEx(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return c.cmd.GetEx(ctx, c.key(key), ttl)
+}
+
+func (c *cmdable) Del(ctx context.Context, key string) error {
+	return c.cmd.Del(ctx, c.key(key))
+}
+
+func (c *cmdable) DelAll(ctx context.Context, keys ...string) error {
+	return c.cmd.DelAll(ctx, c.keys(keys)...)
+}
+
+func (c *cmdable) Incr(ctx context.Context, key string) error {
+	return c.cmd.Incr(ctx, c.key(key))
+}
+
+func (c *cmdable) Decr(ctx context.Context, key string) error {
+	return c.cmd.Decr(ctx, c.key(key))
+}
+
+func (c *cmdable) IncrBy(ctx context.Context, key string, incr int64, ttl time.Duration) (int64, error) {
+	return c.cmd.IncrBy(ctx, c.key(key), incr, ttl)
+}
+
+func (c *cmdable) IncrByFloat(ctx context.Context, key string, incr float64, ttl time.Duration) (float64, error) {
+	return c.cmd.IncrByFloat(ctx, c.key(key), incr, ttl)
+}
+
+func (c *cmdable) DecrBy(ctx context.Context, key string, decr int64, ttl time.Duration) (int64, error) {
+	return c.cmd.DecrBy(ctx, c.key(key), decr, ttl)
+}
+
+func (c *cmdable) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return c.cmd.TTL(ctx, c.key(key))
+}
+
+func (c *cmdable) Expire(ctx context.Context, key string, duration time.Duration) error {
+	return c.cmd.Expire(ctx, c.key(key), duration)
+}
+
+func (c *cmdable) ExpireAt(ctx context.Context, key string, tm time.Time) error {
+	return c.cmd.ExpireAt(ctx, c.key(key), tm)
+}
+
+// Hash commands
+func (c *cmdable) HSet(ctx context.Context, key, field string, value interface{}) error {
+	return c.cmd.HSet(ctx, c.key(key), field, value)
+}
+
+func (c *cmdable) HGet(ctx context.Context, key, field string) (string, error) {
+	return c.cmd.HGet(ctx, c.key(key), field)
+}
+
+func (c *cmdable) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return c.cmd.HGetAll(ctx, c.key(key))
+}
+
+func (c *cmdable) HIncrBy(ctx context.Context, key, field string, incr int64) error {
+	return c.cmd.HIncrBy(ctx, c.key(key), field, incr)
+}
+
+// List commands
+func (c *cmdable) LPush(ctx context.Context, key string, value interface{}) error {
+	return c.cmd.LPush(ctx, c.key(key), value)
+}
+
+func (c *cmdable) LPushAll(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	return c.cmd.LPushAll(ctx, c.key(key), values...)
+}
+
+func (c *cmdable) LPop(ctx context.Context, key string) (string, error) {
+	return c.cmd.LPop(ctx, c.key(key))
+}
+
+func (c *cmdable) RPop(ctx context.Context, key string) (string, error) {
+	return c.cmd.RPop(ctx, c.key(key))
+}
+
+func (c *cmdable) LTrim(ctx context.Context, key string, start, stop int64) error {
+	return c.cmd.LTrim(ctx, c.key(key), start, stop)
+}
+
+func (c *cmdable) LLen(ctx context.Context, key string) (int64, error) {
+	return c.cmd.LLen(ctx, c.key(key))
+}
+
+func (c *cmdable) LRange(ctx context.Context, key string) ([]string, error) {
+	return c.cmd.LRange(ctx, c.key(key))
+}
+
+// Set commands
+func (c *cmdable) SAdd(ctx context.Context, key string, value interface{}) (int64, error) {
+	return c.cmd.SAdd(ctx, c.key(key), value)
+}
+
+func (c *cmdable) SAddAll(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	return c.cmd.SAddAll(ctx, c.key(key), values...)
+}
+
+func (c *cmdable) SRem(ctx context.Context, key string, value interface{}) (int64, error) {
+	return c.cmd.SRem(ctx, c.key(key), value)
+}
+
+func (c *cmdable) SCard(ctx context.Context, key string) (int64, error) {
+	return c.cmd.SCard(ctx, c.key(key))
+}
+
+func (c *cmdable) SIsMember(ctx context.Context, key string, value interface{}) (bool, error) {
+	return c.cmd.SIsMember(ctx, c.key(key), value)
+}
+
+func (c *cmdable) SMembers(ctx context.Context, key string) ([]string, error) {
+	return c.cmd.SMembers(ctx, c.key(key))
+}
+
+// Sorted Set commands
+func (c *cmdable) ZAdd(ctx context.Context, key string, value interface{}) error {
+	return c.cmd.ZAdd(ctx, c.key(key), value)
+}
+
+func (c *cmdable) ZAddWithScore(ctx context.Context, key string, score float64, value interface{}) error {
+	return c.cmd.ZAddWithScore(ctx, c.key(key), score, value)
+}
+
+func (c *cmdable) ZRem(ctx context.Context, key string, value interface{}) (int64, error) {
+	return c.cmd.ZRem(ctx, c.key(key), value)
+}
+
+func (c *cmdable) ZPopMin(ctx context.Context, key string, count int64) ([]string, error) {
+	return c.cmd.ZPopMin(ctx, c.key(key), count)
+}
+
+func (c *cmdable) ZCount(ctx context.Context, key string) (int64, error) {
+	return c.cmd.ZCount(ctx, c.key(key))
+}
+
+func (c *cmdable) ZRange(ctx context.Context, key string) ([]string, error) {
+	return c.cmd.ZRange(ctx, c.key(key))
+}
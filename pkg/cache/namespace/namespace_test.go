@@ -0,0 +1,300 @@
+package namespace_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/microservice-common/pkg/cache"
+	"github.com/8thgencore/microservice-common/pkg/cache/memory"
+	"github.com/8thgencore/microservice-common/pkg/cache/namespace"
+)
+
+// The namespace has glob characters which must be matched literally,
+// e.g. "o*:v1:*" would match keys of "orders" too.
+const (
+	ns     = "o*"
+	prefix = "o*:v1:"
+)
+
+// seed stores the keys in the remote cache.
+func seed(t *testing.T, remote cache.Client, keys map[string]string) {
+	t.Helper()
+
+	for key, val := range keys {
+		if err := remote.Set(context.Background(), key, val); err != nil {
+			t.Fatalf("Set(%q): %v", key, err)
+		}
+	}
+}
+
+// check compares the keys of the remote cache with want, empty value means the key must be absent.
+func check(t *testing.T, remote cache.Client, want map[string]string) {
+	t.Helper()
+
+	for key, wantVal := range want {
+		val, err := remote.Get(context.Background(), key)
+		switch {
+		case wantVal == "" && !errors.Is(err, cache.ErrKeyNotFound):
+			t.Errorf("Get(%q) = %q, %v, want key deleted", key, val, err)
+		case wantVal != "" && (err != nil || val != wantVal):
+			t.Errorf("Get(%q) = %q, %v, want %q", key, val, err, wantVal)
+		}
+	}
+}
+
+func receive(t *testing.T, sub cache.Subscription) cache.Message {
+	t.Helper()
+
+	select {
+	case msg := <-sub.Channel():
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("no message received")
+
+		return cache.Message{}
+	}
+}
+
+func TestKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(ctx context.Context, c cache.Client) error
+		want map[string]string
+	}{
+		{
+			name: "set",
+			fn:   func(ctx context.Context, c cache.Client) error { return c.Set(ctx, "a", "new") },
+			want: map[string]string{prefix + "a": "new", "a": "foreign", "o*:v0:a": "old"},
+		},
+		{
+			name: "del all",
+			fn:   func(ctx context.Context, c cache.Client) error { return c.DelAll(ctx, "a", "b") },
+			want: map[string]string{prefix + "a": "", prefix + "b": "", "a": "foreign", "o*:v0:a": "old"},
+		},
+		{
+			name: "pipeline",
+			fn: func(ctx context.Context, c cache.Client) error {
+				return c.Pipeline(ctx, func(p cache.Pipeliner) error {
+					p.Set(ctx, "a", "new")
+					p.DelAll(ctx, "b")
+
+					return nil
+				})
+			},
+			want: map[string]string{prefix + "a": "new", prefix + "b": "", "a": "foreign", "b": "foreign"},
+		},
+		{
+			name: "watch",
+			fn: func(ctx context.Context, c cache.Client) error {
+				return c.Watch(ctx, []string{"a"}, func(tx cache.Tx) error {
+					val, err := tx.Get(ctx, "a")
+					if err != nil {
+						return err
+					}
+
+					return tx.Exec(ctx, func(p cache.Pipeliner) error {
+						p.Set(ctx, "b", val+"!")
+
+						return nil
+					})
+				})
+			},
+			want: map[string]string{prefix + "b": "1!", "b": "foreign"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remote := memory.NewClient()
+			seed(t, remote, map[string]string{
+				prefix + "a": "1",
+				prefix + "b": "2",
+				"a":          "foreign",
+				"b":          "foreign",
+				"o*:v0:a":    "old",
+			})
+
+			if err := tt.fn(context.Background(), namespace.New(remote, ns, 1)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			check(t, remote, tt.want)
+		})
+	}
+}
+
+func TestWatchKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		changed string
+		wantErr error
+	}{
+		{name: "watched key changed", changed: prefix + "a", wantErr: cache.ErrTxFailed},
+		{name: "foreign key changed", changed: "a", wantErr: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			remote := memory.NewClient()
+			c := namespace.New(remote, ns, 1)
+
+			err := c.Watch(ctx, []string{"a"}, func(tx cache.Tx) error {
+				seed(t, remote, map[string]string{tt.changed: "changed"})
+
+				return tx.Exec(ctx, func(p cache.Pipeliner) error {
+					p.Set(ctx, "a", "new")
+
+					return nil
+				})
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Watch error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScan(t *testing.T) {
+	keys := map[string]string{
+		prefix + "x":  "1",
+		prefix + "xy": "1",
+		prefix + "y":  "1",
+		"x":           "foreign",
+		"or:v1:x":     "foreign",
+		"o*:v2:x":     "foreign",
+	}
+
+	tests := []struct {
+		name  string
+		match string
+		want  []string
+	}{
+		{name: "all", match: "", want: []string{"x", "xy", "y"}},
+		{name: "glob", match: "x*", want: []string{"x", "xy"}},
+		{name: "literal", match: "y", want: []string{"y"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			remote := memory.NewClient()
+			seed(t, remote, keys)
+			c := namespace.New(remote, ns, 1)
+
+			var got []string
+			for key, err := range c.Scan(ctx, tt.match, 2) {
+				if err != nil {
+					t.Fatalf("Scan: %v", err)
+				}
+				got = append(got, key)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Scan(%q) = %v, want %v", tt.match, got, tt.want)
+			}
+
+			if err := cache.DeleteByPattern(ctx, c, tt.match, 2); err != nil {
+				t.Fatalf("DeleteByPattern: %v", err)
+			}
+			want := make(map[string]string, len(keys))
+			for key, val := range keys {
+				want[key] = val
+			}
+			for _, key := range tt.want {
+				want[prefix+key] = ""
+			}
+			check(t, remote, want)
+		})
+	}
+}
+
+func TestPublish(t *testing.T) {
+	tests := []struct {
+		name    string
+		publish func(ctx context.Context, c cache.Client) error
+	}{
+		{
+			name:    "client",
+			publish: func(ctx context.Context, c cache.Client) error { return c.Publish(ctx, "events", "msg") },
+		},
+		{
+			name: "pipeline",
+			publish: func(ctx context.Context, c cache.Client) error {
+				return c.Pipeline(ctx, func(p cache.Pipeliner) error {
+					p.Publish(ctx, "events", "msg")
+
+					return nil
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			remote := memory.NewClient()
+			sub, err := remote.Subscribe(ctx, "o*:events")
+			if err != nil {
+				t.Fatalf("Subscribe: %v", err)
+			}
+			defer sub.Close()
+
+			if err := tt.publish(ctx, namespace.New(remote, ns, 1)); err != nil {
+				t.Fatalf("publish: %v", err)
+			}
+			if msg := receive(t, sub); msg.Payload != "msg" {
+				t.Errorf("Payload = %q, want %q", msg.Payload, "msg")
+			}
+		})
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	tests := []struct {
+		name        string
+		subscribe   func(ctx context.Context, c cache.Client) (cache.Subscription, error)
+		wantPattern string
+	}{
+		{
+			name: "subscribe",
+			subscribe: func(ctx context.Context, c cache.Client) (cache.Subscription, error) {
+				return c.Subscribe(ctx, "events")
+			},
+		},
+		{
+			name: "psubscribe",
+			subscribe: func(ctx context.Context, c cache.Client) (cache.Subscription, error) {
+				return c.PSubscribe(ctx, "ev*")
+			},
+			wantPattern: "ev*",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			remote := memory.NewClient()
+			sub, err := tt.subscribe(ctx, namespace.New(remote, ns, 1))
+			if err != nil {
+				t.Fatalf("subscribe: %v", err)
+			}
+			defer sub.Close()
+
+			// Messages of other namespaces are published first, so they would be received first.
+			for _, channel := range []string{"events", "or:events", "o*:events"} {
+				if err := remote.Publish(ctx, channel, channel); err != nil {
+					t.Fatalf("Publish(%q): %v", channel, err)
+				}
+			}
+
+			msg := receive(t, sub)
+			want := cache.Message{Channel: "events", Pattern: tt.wantPattern, Payload: "o*:events"}
+			if msg != want {
+				t.Errorf("message = %+v, want %+v", msg, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package namespace
+
+import (
+	"context"
+	"time"
+
+	"github.com/8thgencore/microservice-common/pkg/cache"
+)
+
+// pipeline prefixes keys of the queued commands.
+type pipeline struct {
+	pipe          cache.Pipeliner
+	prefix        string
+	channelPrefix string
+}
+
+// String commands
+func (p *pipeline) Set(ctx context.Context, key string, value interface{}) *cache.Result[struct{}] {
+	return p.pipe.Set(ctx, p.prefix+key, value)
+}
+
+func (p *pipeline) SetEx(
+	ctx context.Context, key string, value interface{}, duration time.Duration,
+) *cache.Result[struct{}] {
+	return p.pipe.SetEx(ctx, p.prefix+key, value, duration)
+}
+
+func (p *pipeline) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) *cache.Result[bool] {
+	return p.pipe.SetNX(ctx, p.prefix+key, value, ttl)
+}
+
+func (p *pipeline) SetXX(ctx context.Context, key string, value interface{}, ttl time.Duration) *cache.Result[bool] {
+	return p.pipe.SetXX(ctx, p.prefix+key, value, ttl)
+}
+
+func (p *pipeline) Get(ctx context.Context, key string) *cache.Result[string] {
+	return p.pipe.Get(ctx, p.prefix+key)
+}
+
+func (p *pipeline) GetSet(
+	ctx context.Context, key string, value interface{}, ttl time.Duration,
+) *cache.Result[string] {
+	return p.pipe.GetSet(ctx, p.prefix+key, value, ttl)
+}
+
+func (p *pipeline) GetDel(ctx context.Context, key string) *cache.Result[string] {
+	return p.pipe.GetDel(ctx, p.prefix+key)
+}
+
+func (p *pipeline) GetEx(ctx context.Context, key string, ttl time.Duration) *cache.Result[string] {
+	return p.pipe.GetEx(ctx, p.prefix+key, ttl)
+}
+
+func (p *pipeline) Del(ctx context.Context, key string) *cache.Result[struct{}] {
+	return p.pipe.Del(ctx, p.prefix+key)
+}
+
+func (p *pipeline) DelAll(ctx context.Context, keys ...string) *cache.Result[struct{}] {
+	return p.pipe.DelAll(ctx, withPrefix(p.prefix, keys)...)
+}
+
+func (p *pipeline) Incr(ctx context.Context, key string) *cache.Result[struct{}] {
+	return p.pipe.Incr(ctx, p.prefix+key)
+}
+
+func (p *pipeline) Decr(ctx context.Context, key string) *cache.Result[struct{}] {
+	return p.pipe.Decr(ctx, p.prefix+key)
+}
+
+func (p *pipeline) IncrBy(ctx context.Context, key string, incr int64, ttl time.Duration) *cache.Result[int64] {
+	return p.pipe.IncrBy(ctx, p.prefix+key, incr, ttl)
+}
+
+func (p *pipeline) IncrByFloat(
+	ctx context.Context, key string, incr float64, ttl time.Duration,
+) *cache.Result[float64] {
+	return p.pipe.IncrByFloat(ctx, p.prefix+key, incr, ttl)
+}
+
+func (p *pipeline) DecrBy(ctx context.Context, key string, decr int64, ttl time.Duration) *cache.Result[int64] {
+	return p.pipe.DecrBy(ctx, p.prefix+key, decr, ttl)
+}
+
+func (p *pipeline) TTL(ctx context.Context, key string) *cache.Result[time.Duration] {
+	return p.pipe.TTL(ctx, p.prefix+key)
+}
+
+func (p *pipeline) Expire(ctx context.Context, key string, expiration time.Duration) *cache.Result[struct{}] {
+	return p.pipe.Expire(ctx, p.prefix+key, expiration)
+}
+
+func (p *pipeline) ExpireAt(ctx context.Context, key string, tm time.Time) *cache.Result[struct{}] {
+	return p.pipe.ExpireAt(ctx, p.prefix+key, tm)
+}
+
+// Hash commands
+func (p *pipeline) HSet(ctx context.Context, key, field string, value interface{}) *cache.Result[struct{}] {
+	return p.pipe.HSet(ctx, p.prefix+key, field, value)
+}
+
+func (p *pipeline) HGet(ctx context.Context, key, field string) *cache.Result[string] {
+	return p.pipe.HGet(ctx, p.prefix+key, field)
+}
+
+func (p *pipeline) HGetAll(ctx context.Context, key string) *cache.Result[map[string]string] {
+	return p.pipe.HGetAll(ctx, p.prefix+key)
+}
+
+func (p *pipeline) HIncrBy(ctx context.Context, key, field string, incr int64) *cache.Result[struct{}] {
+	return p.pipe.HIncrBy(ctx, p.prefix+key, field, incr)
+}
+
+// List commands
+func (p *pipeline) LPush(ctx context.Context, key string, value interface{}) *cache.Result[struct{}] {
+	return p.pipe.LPush(ctx, p.prefix+key, value)
+}
+
+func (p *pipeline) LPushAll(ctx context.Context, key string, values ...interface{}) *cache.Result[int64] {
+	return p.pipe.LPushAll(ctx, p.prefix+key, values...)
+}
+
+func (p *pipeline) LPop(ctx context.Context, key string) *cache.Result[string] {
+	return p.pipe.LPop(ctx, p.prefix+key)
+}
+
+func (p *pipeline) RPop(ctx context.Context, key string) *cache.Result[string] {
+	return p.pipe.RPop(ctx, p.prefix+key)
+}
+
+func (p *pipeline) LTrim(ctx context.Context, key string, start, stop int64) *cache.Result[struct{}] {
+	return p.pipe.LTrim(ctx, p.prefix+key, start, stop)
+}
+
+func (p *pipeline) LLen(ctx context.Context, key string) *cache.Result[int64] {
+	return p.pipe.LLen(ctx, p.prefix+key)
+}
+
+func (p *pipeline) LRange(ctx context.Context, key string) *cache.Result[[]string] {
+	return p.pipe.LRange(ctx, p.prefix+key)
+}
+
+// Set commands
+func (p *pipeline) SAdd(ctx context.Context, key string, value interface{}) *cache.Result[int64] {
+	return p.pipe.SAdd(ctx, p.prefix+key, value)
+}
+
+func (p *pipeline) SAddAll(ctx context.Context, key string, values ...interface{}) *cache.Result[int64] {
+	return p.pipe.SAddAll(ctx, p.prefix+key, values...)
+}
+
+func (p *pipeline) SRem(ctx context.Context, key string, value interface{}) *cache.Result[int64] {
+	return p.pipe.SRem(ctx, p.prefix+key, value)
+}
+
+func (p *pipeline) SCard(ctx context.Context, key string) *cache.Result[int64] {
+	return p.pipe.SCard(ctx, p.prefix+key)
+}
+
+func (p *pipeline) SIsMember(ctx context.Context, key string, value interface{}) *cache.Result[bool] {
+	return p.pipe.SIsMember(ctx, p.prefix+key, value)
+}
+
+func (p *pipeline) SMembers(ctx context.Context, key string) *cache.Result[[]string] {
+	return p.pipe.SMembers(ctx, p.prefix+key)
+}
+
+// Sorted Set commands
+func (p *pipeline) ZAdd(ctx context.Context, key string, value interface{}) *cache.Result[struct{}] {
+	return p.pipe.ZAdd(ctx, p.prefix+key, value)
+}
+
+func (p *pipeline) ZAddWithScore(
+	ctx context.Context, key string, score float64, value interface{},
+) *cache.Result[struct{}] {
+	return p.pipe.ZAddWithScore(ctx, p.prefix+key, score, value)
+}
+
+func (p *pipeline) ZRem(ctx context.Context, key string, value interface{}) *cache.Result[int64] {
+	return p.pipe.ZRem(ctx, p.prefix+key, value)
+}
+
+func (p *pipeline) ZPopMin(ctx context.Context, key string, count int64) *cache.Result[[]string] {
+	return p.pipe.ZPopMin(ctx, p.prefix+key, count)
+}
+
+func (p *pipeline) ZCount(ctx context.Context, key string) *cache.Result[int64] {
+	return p.pipe.ZCount(ctx, p.prefix+key)
+}
+
+func (p *pipeline) ZRange(ctx context.Context, key string) *cache.Result[[]string] {
+	return p.pipe.ZRange(ctx, p.prefix+key)
+}
+
+// Pub/Sub commands
+func (p *pipeline) Publish(ctx context.Context, channel string, message interface{}) *cache.Result[struct{}] {
+	return p.pipe.Publish(ctx, p.channelPrefix+channel, message)
+}
@@ -0,0 +1,102 @@
+package namespace
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/8thgencore/microservice-common/pkg/cache"
+)
+
+// Pub/Sub commands
+func (c *cacheClient) Publish(ctx context.Context, channel string, message interface{}) error {
+	return c.client.Publish(ctx, c.channelPrefix+channel, message)
+}
+
+func (c *cacheClient) Subscribe(ctx context.Context, channels ...string) (cache.Subscription, error) {
+	sub, err := c.client.Subscribe(ctx, withPrefix(c.channelPrefix, channels)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.subscribe(sub), nil
+}
+
+func (c *cacheClient) PSubscribe(ctx context.Context, patterns ...string) (cache.Subscription, error) {
+	// The namespace is matched literally even if it has glob characters.
+	sub, err := c.client.PSubscribe(ctx, withPrefix(escapeGlob(c.channelPrefix), patterns)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.subscribe(sub), nil
+}
+
+// subscription strips the namespace from channels and patterns of received messages.
+type subscription struct {
+	sub    cache.Subscription
+	ch     chan cache.Message
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+	prefix string
+	escape string
+}
+
+func (c *cacheClient) subscribe(sub cache.Subscription) *subscription {
+	s := &subscription{
+		sub:    sub,
+		ch:     make(chan cache.Message),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+		prefix: c.channelPrefix,
+		escape: escapeGlob(c.channelPrefix),
+	}
+	go s.run()
+
+	return s
+}
+
+func (s *subscription) run() {
+	defer close(s.done)
+	defer close(s.ch)
+
+	for msg := range s.sub.Channel() {
+		msg.Channel = strings.TrimPrefix(msg.Channel, s.prefix)
+		msg.Pattern = strings.TrimPrefix(msg.Pattern, s.escape)
+		select {
+		case s.ch <- msg:
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *subscription) Channel() <-chan cache.Message {
+	return s.ch
+}
+
+func (s *subscription) Close() error {
+	var err error
+	s.once.Do(func() {
+		close(s.stop)
+		err = s.sub.Close()
+		<-s.done
+	})
+
+	return err
+}
+
+// escapeGlob escapes special characters of redis glob patterns in s.
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
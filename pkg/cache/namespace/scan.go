@@ -0,0 +1,39 @@
+package namespace
+
+import (
+	"context"
+	"iter"
+	"strings"
+
+	"github.com/8thgencore/microservice-common/pkg/cache"
+)
+
+// Scan commands
+func (c *cacheClient) Scan(ctx context.Context, match string, count int64) iter.Seq2[string, error] {
+	if match == "" {
+		match = "*"
+	}
+
+	return func(yield func(string, error) bool) {
+		// Only keys of the namespace and version are scanned and yielded without the prefix.
+		for key, err := range c.client.Scan(ctx, escapeGlob(c.prefix)+match, count) {
+			if !yield(strings.TrimPrefix(key, c.prefix), err) {
+				return
+			}
+		}
+	}
+}
+
+func (c *cacheClient) HScan(ctx context.Context, key, match string, count int64) iter.Seq2[cache.HashField, error] {
+	return c.client.HScan(ctx, c.key(key), match, count)
+}
+
+func (c *cacheClient) SScan(ctx context.Context, key, match string, count int64) iter.Seq2[string, error] {
+	return c.client.SScan(ctx, c.key(key), match, count)
+}
+
+func (c *cacheClient) ZScan(
+	ctx context.Context, key, match string, count int64,
+) iter.Seq2[cache.ScoredMember, error] {
+	return c.client.ZScan(ctx, c.key(key), match, count)
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Result is a typed result of a command queued in Pipeliner. It is available once
+// the pipeline or the transaction has been executed.
+type Result[T any] struct {
+	fn func() (T, error)
+}
+
+// NewResult creates Result reading the value and the error of the command with fn.
+// It is meant for implementations of Pipeliner.
+func NewResult[T any](fn func() (T, error)) *Result[T] {
+	return &Result[T]{fn: fn}
+}
+
+// Result returns the value and the error of the command.
+func (r *Result[T]) Result() (T, error) {
+	return r.fn()
+}
+
+// Val returns the value of the command.
+func (r *Result[T]) Val() T {
+	val, _ := r.fn()
+
+	return val
+}
+
+// Err returns the error of the command, e.g. ErrKeyNotFound.
+func (r *Result[T]) Err() error {
+	_, err := r.fn()
+
+	return err
+}
+
+// Pipeliner queues commands which are sent to the storage in a single round trip.
+// Commands mirror Client ones, their results are available after execution.
+type Pipeliner interface {
+	// String commands
+	Set(ctx context.Context, key string, value interface{}) *Result[struct{}]
+	SetEx(ctx context.Context, key string, value interface{}, duration time.Duration) *Result[struct{}]
+	SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) *Result[bool]
+	SetXX(ctx context.Context, key string, value interface{}, ttl time.Duration) *Result[bool]
+	Get(ctx context.Context, key string) *Result[string]
+	GetSet(ctx context.Context, key string, value interface{}, ttl time.Duration) *Result[string]
+	GetDel(ctx context.Context, key string) *Result[string]
+	GetEx(ctx context.Context, key string, ttl time.Duration) *Result[string]
+	Del(ctx context.Context, key string) *Result[struct{}]
+	DelAll(ctx context.Context, keys ...string) *Result[struct{}]
+	Incr(ctx context.Context, key string) *Result[struct{}]
+	Decr(ctx context.Context, key string) *Result[struct{}]
+	IncrBy(ctx context.Context, key string, incr int64, ttl time.Duration) *Result[int64]
+	IncrByFloat(ctx context.Context, key string, incr float64, ttl time.Duration) *Result[float64]
+	DecrBy(ctx context.Context, key string, decr int64, ttl time.Duration) *Result[int64]
+	TTL(ctx context.Context, key string) *Result[time.Duration]
+	Expire(ctx context.Context, key string, expiration time.Duration) *Result[struct{}]
+	ExpireAt(ctx context.Context, key string, tm time.Time) *Result[struct{}]
+
+	// Hash commands
+	HSet(ctx context.Context, key, field string, value interface{}) *Result[struct{}]
+	HGet(ctx context.Context, key, field string) *Result[string]
+	HGetAll(ctx context.Context, key string) *Result[map[string]string]
+	HIncrBy(ctx context.Context, key, field string, incr int64) *Result[struct{}]
+
+	// List commands
+	LPush(ctx context.Context, key string, value interface{}) *Result[struct{}]
+	LPushAll(ctx context.Context, key string, values ...interface{}) *Result[int64]
+	LPop(ctx context.Context, key string) *Result[string]
+	RPop(ctx context.Context, key string) *Result[string]
+	LTrim(ctx context.Context, key string, start, stop int64) *Result[struct{}]
+	LLen(ctx context.Context, key string) *Result[int64]
+	LRange(ctx context.Context, key string) *Result[[]string]
+
+	// Set commands
+	SAdd(ctx context.Context, key string, value interface{}) *Result[int64]
+	SAddAll(ctx context.Context, key string, values ...interface{}) *Result[int64]
+	SRem(ctx context.Context, key string, value interface{}) *Result[int64]
+	SCard(ctx context.Context, key string) *Result[int64]
+	SIsMember(ctx context.Context, key string, value interface{}) *Result[bool]
+	SMembers(ctx context.Context, key string) *Result[[]string]
+
+	// Sorted Set commands
+	ZAdd(ctx context.Context, key string, value interface{}) *Result[struct{}]
+	ZAddWithScore(ctx context.Context, key string, score float64, value interface{}) *Result[struct{}]
+	ZRem(ctx context.Context, key string, value interface{}) *Result[int64]
+	ZPopMin(ctx context.Context, key string, count int64) *Result[[]string]
+	ZCount(ctx context.Context, key string) *Result[int64]
+	ZRange(ctx context.Context, key string) *Result[[]string]
+
+	// Pub/Sub commands
+	Publish(ctx context.Context, channel string, message interface{}) *Result[struct{}]
+}
+
+// Tx is an optimistic transaction started by Client.Watch. Its commands are executed
+// immediately, e.g. to read the watched keys, while commands queued by Exec are applied
+// atomically only if none of the watched keys has been changed since Watch.
+type Tx interface {
+	Cmdable
+
+	// Exec queues commands with fn and executes them in MULTI/EXEC. It returns ErrTxFailed
+	// if any of the watched keys has been changed, so the caller may retry the whole Watch.
+	Exec(ctx context.Context, fn func(p Pipeliner) error) error
+}
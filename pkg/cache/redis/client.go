@@ -2,33 +2,58 @@ package redis
 
 import (
 	"context"
-	"errors"
 	"log/slog"
 	"time"
 
-	"github.com/8thgencore/microservice-common/pkg/logger/sl"
+	"github.com/8thgencore/microservice-common/pkg/cache"
 	"github.com/redis/go-redis/v9"
 )
 
-// ErrKeyNotFound is returned when a key is not found in a map or other data structure
-var ErrKeyNotFound = errors.New("key not found")
+// ErrKeyNotFound is returned when a key is not found in a map or other data structure.
+// It is the same error as cache.ErrKeyNotFound.
+var ErrKeyNotFound = cache.ErrKeyNotFound
 
 type cacheClient struct {
-	rdb *redis.Client
-	log *slog.Logger
+	// rdb runs the commands, it is either the client or a transaction of Watch.
+	rdb    redis.Cmdable
+	client redis.UniversalClient
+	log    *slog.Logger
+	// cluster is set for Redis Cluster, where keys of a multi-key command must belong to one hash slot.
+	cluster bool
 }
 
 // NewClient creates client for Redis communication.
 func NewClient(opt *redis.Options, log *slog.Logger) *cacheClient {
-	rdb := redis.NewClient(opt)
-	return &cacheClient{rdb: rdb, log: log}
+	return newClient(redis.NewClient(opt), log)
+}
+
+// NewFailoverClient creates client for Redis managed by Sentinel, which follows the master on failover.
+func NewFailoverClient(opt *redis.FailoverOptions, log *slog.Logger) *cacheClient {
+	return newClient(redis.NewFailoverClient(opt), log)
+}
+
+// NewClusterClient creates client for Redis Cluster.
+// Keys watched by Watch and used in Lua scripts must belong to one hash slot, e.g. share a {hash tag}.
+func NewClusterClient(opt *redis.ClusterOptions, log *slog.Logger) *cacheClient {
+	return newClient(redis.NewClusterClient(opt), log)
+}
+
+// NewUniversalClient creates client for a single node, Sentinel or Cluster depending on the options,
+// see redis.NewUniversalClient.
+func NewUniversalClient(opt *redis.UniversalOptions, log *slog.Logger) *cacheClient {
+	return newClient(redis.NewUniversalClient(opt), log)
+}
+
+func newClient(rdb redis.UniversalClient, log *slog.Logger) *cacheClient {
+	_, cluster := rdb.(*redis.ClusterClient)
+
+	return &cacheClient{rdb: rdb, client: rdb, log: log, cluster: cluster}
 }
 
 // String commands
 func (c *cacheClient) Set(ctx context.Context, key string, value interface{}) error {
 	if err := c.rdb.Set(ctx, key, value, 0).Err(); err != nil {
-		c.log.Error("unable to set key in the cache", slog.String("key", key))
-		return err
+		return c.fail(err, "unable to set key in the cache", slog.String("key", key))
 	}
 
 	return nil
@@ -36,21 +61,74 @@ func (c *cacheClient) Set(ctx context.Context, key string, value interface{}) er
 
 func (c *cacheClient) SetEx(ctx context.Context, key string, value interface{}, duration time.Duration) error {
 	if err := c.rdb.SetEx(ctx, key, value, duration).Err(); err != nil {
-		c.log.Error("unable to set key in the cache", slog.String("key", key))
-		return err
+		return c.fail(err, "unable to set key in the cache", slog.String("key", key))
 	}
 
 	return nil
 }
 
+func (c *cacheClient) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	ok, err := c.rdb.SetNX(ctx, key, value, ttl).Result()
+	if err != nil {
+		return false, c.fail(err, "unable to setnx key in the cache", slog.String("key", key))
+	}
+
+	return ok, nil
+}
+
+func (c *cacheClient) SetXX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	ok, err := c.rdb.SetXX(ctx, key, value, ttl).Result()
+	if err != nil {
+		return false, c.fail(err, "unable to setxx key in the cache", slog.String("key", key))
+	}
+
+	return ok, nil
+}
+
 func (c *cacheClient) Get(ctx context.Context, key string) (string, error) {
 	val, err := c.rdb.Get(ctx, key).Result()
 	if err != nil {
-		if err.Error() == redis.Nil.Error() {
-			return "", ErrKeyNotFound
-		}
-		c.log.Error("unable to get key from the cache", slog.String("key", key))
-		return "", err
+		return "", c.fail(err, "unable to get key from the cache", slog.String("key", key))
+	}
+
+	return val, nil
+}
+
+func (c *cacheClient) GetSet(ctx context.Context, key string, value interface{}, ttl time.Duration) (string, error) {
+	val, err := c.rdb.SetArgs(ctx, key, value, getSetArgs(ttl)).Result()
+	if err != nil {
+		return "", c.fail(err, "unable to getset key in the cache", slog.String("key", key))
+	}
+
+	return val, nil
+}
+
+// getSetArgs returns arguments of SET ... GET with ttl handled like SetXX does.
+func getSetArgs(ttl time.Duration) redis.SetArgs {
+	args := redis.SetArgs{Get: true}
+	switch {
+	case ttl == cache.KeepTTL:
+		args.KeepTTL = true
+	case ttl > 0:
+		args.TTL = ttl
+	}
+
+	return args
+}
+
+func (c *cacheClient) GetDel(ctx context.Context, key string) (string, error) {
+	val, err := c.rdb.GetDel(ctx, key).Result()
+	if err != nil {
+		return "", c.fail(err, "unable to getdel key in the cache", slog.String("key", key))
+	}
+
+	return val, nil
+}
+
+func (c *cacheClient) GetEx(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	val, err := c.rdb.GetEx(ctx, key, ttl).Result()
+	if err != nil {
+		return "", c.fail(err, "unable to getex key in the cache", slog.String("key", key))
 	}
 
 	return val, nil
@@ -58,8 +136,7 @@ func (c *cacheClient) Get(ctx context.Context, key string) (string, error) {
 
 func (c *cacheClient) Del(ctx context.Context, key string) error {
 	if _, err := c.rdb.Del(ctx, key).Result(); err != nil {
-		c.log.Error("unable to del key in the cache", slog.String("key", key))
-		return err
+		return c.fail(err, "unable to del key in the cache", slog.String("key", key))
 	}
 
 	return nil
@@ -69,9 +146,26 @@ func (c *cacheClient) DelAll(ctx context.Context, keys ...string) error {
 	if len(keys) == 0 {
 		return nil // No keys to delete
 	}
+	if c.cluster {
+		return c.delEach(ctx, keys)
+	}
 	if _, err := c.rdb.Del(ctx, keys...).Result(); err != nil {
-		c.log.Error("unable to DelAll keys in the cache")
-		return err
+		return c.fail(err, "unable to DelAll keys in the cache")
+	}
+
+	return nil
+}
+
+// delEach deletes keys one by one in a pipeline, which the cluster client splits by hash slots.
+func (c *cacheClient) delEach(ctx context.Context, keys []string) error {
+	if _, err := c.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Del(ctx, key)
+		}
+
+		return nil
+	}); err != nil {
+		return c.fail(err, "unable to DelAll keys in the cache")
 	}
 
 	return nil
@@ -79,8 +173,7 @@ func (c *cacheClient) DelAll(ctx context.Context, keys ...string) error {
 
 func (c *cacheClient) Incr(ctx context.Context, key string) error {
 	if err := c.rdb.Incr(ctx, key).Err(); err != nil {
-		c.log.Error("unable to incr key in the cache", slog.String("key", key))
-		return err
+		return c.fail(err, "unable to incr key in the cache", slog.String("key", key))
 	}
 
 	return nil
@@ -88,18 +181,82 @@ func (c *cacheClient) Incr(ctx context.Context, key string) error {
 
 func (c *cacheClient) Decr(ctx context.Context, key string) error {
 	if err := c.rdb.Decr(ctx, key).Err(); err != nil {
-		c.log.Error("unable to decr key in the cache", slog.String("key", key))
-		return err
+		return c.fail(err, "unable to decr key in the cache", slog.String("key", key))
 	}
 
 	return nil
 }
 
+// KEYS[1] - counter key; ARGV[1] - INCRBY, DECRBY or INCRBYFLOAT, ARGV[2] - increment, ARGV[3] - ttl in ms.
+var counterScript = redis.NewScript(`
+local val = redis.call(ARGV[1], KEYS[1], ARGV[2])
+if redis.call("PTTL", KEYS[1]) == -1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[3])
+end
+return val
+`)
+
+func (c *cacheClient) IncrBy(ctx context.Context, key string, incr int64, ttl time.Duration) (int64, error) {
+	var (
+		val int64
+		err error
+	)
+	if ttl > 0 {
+		val, err = counterScript.Run(ctx, c.rdb, []string{key}, "INCRBY", incr, ttlMs(ttl)).Int64()
+	} else {
+		val, err = c.rdb.IncrBy(ctx, key, incr).Result()
+	}
+	if err != nil {
+		return 0, c.fail(err, "unable to incrby key in the cache", slog.String("key", key))
+	}
+
+	return val, nil
+}
+
+func (c *cacheClient) IncrByFloat(ctx context.Context, key string, incr float64, ttl time.Duration) (float64, error) {
+	var (
+		val float64
+		err error
+	)
+	if ttl > 0 {
+		// INCRBYFLOAT replies with a string which is returned by the script as is.
+		val, err = counterScript.Run(ctx, c.rdb, []string{key}, "INCRBYFLOAT", incr, ttlMs(ttl)).Float64()
+	} else {
+		val, err = c.rdb.IncrByFloat(ctx, key, incr).Result()
+	}
+	if err != nil {
+		return 0, c.fail(err, "unable to incrbyfloat key in the cache", slog.String("key", key))
+	}
+
+	return val, nil
+}
+
+func (c *cacheClient) DecrBy(ctx context.Context, key string, decr int64, ttl time.Duration) (int64, error) {
+	var (
+		val int64
+		err error
+	)
+	if ttl > 0 {
+		val, err = counterScript.Run(ctx, c.rdb, []string{key}, "DECRBY", decr, ttlMs(ttl)).Int64()
+	} else {
+		val, err = c.rdb.DecrBy(ctx, key, decr).Result()
+	}
+	if err != nil {
+		return 0, c.fail(err, "unable to decrby key in the cache", slog.String("key", key))
+	}
+
+	return val, nil
+}
+
+// ttlMs returns ttl in milliseconds rounding sub-millisecond ttl up like go-redis does.
+func ttlMs(ttl time.Duration) int64 {
+	return max(ttl.Milliseconds(), 1)
+}
+
 func (c *cacheClient) TTL(ctx context.Context, key string) (time.Duration, error) {
 	expiresAt, err := c.rdb.TTL(ctx, key).Result()
 	if err != nil {
-		c.log.Error("unable to ttl key in the cache", slog.String("key", key))
-		return expiresAt, err
+		return expiresAt, c.fail(err, "unable to ttl key in the cache", slog.String("key", key))
 	}
 
 	return expiresAt, nil
@@ -107,8 +264,7 @@ func (c *cacheClient) TTL(ctx context.Context, key string) (time.Duration, error
 
 func (c *cacheClient) Expire(ctx context.Context, key string, duration time.Duration) error {
 	if err := c.rdb.Expire(ctx, key, duration).Err(); err != nil {
-		c.log.Error("unable to expire key in the cache", slog.String("key", key))
-		return err
+		return c.fail(err, "unable to expire key in the cache", slog.String("key", key))
 	}
 
 	return nil
@@ -116,8 +272,7 @@ func (c *cacheClient) Expire(ctx context.Context, key string, duration time.Dura
 
 func (c *cacheClient) ExpireAt(ctx context.Context, key string, tm time.Time) error {
 	if err := c.rdb.ExpireAt(ctx, key, tm).Err(); err != nil {
-		c.log.Error("unable to expire key in the cache", slog.String("key", key))
-		return err
+		return c.fail(err, "unable to expire key in the cache", slog.String("key", key))
 	}
 
 	return nil
@@ -126,8 +281,8 @@ func (c *cacheClient) ExpireAt(ctx context.Context, key string, tm time.Time) er
 // Hash commands
 func (c *cacheClient) HSet(ctx context.Context, key, field string, value interface{}) error {
 	if err := c.rdb.HSet(ctx, key, field, value).Err(); err != nil {
-		c.log.Error("unable to set field in the hash", slog.String("key", key), slog.String("field", field))
-		return err
+		return c.fail(err, "unable to set field in the hash",
+			slog.String("key", key), slog.String("field", field))
 	}
 
 	return nil
@@ -136,8 +291,8 @@ func (c *cacheClient) HSet(ctx context.Context, key, field string, value interfa
 func (c *cacheClient) HGet(ctx context.Context, key, field string) (string, error) {
 	result, err := c.rdb.HGet(ctx, key, field).Result()
 	if err != nil {
-		c.log.Error("unable to get field from the hash", slog.String("key", key), slog.String("field", field))
-		return "", err
+		return "", c.fail(err, "unable to get field from the hash",
+			slog.String("key", key), slog.String("field", field))
 	}
 
 	return result, nil
@@ -146,8 +301,7 @@ func (c *cacheClient) HGet(ctx context.Context, key, field string) (string, erro
 func (c *cacheClient) HGetAll(ctx context.Context, key string) (map[string]string, error) {
 	result, err := c.rdb.HGetAll(ctx, key).Result()
 	if err != nil {
-		c.log.Error("unable to get all fields from the hash", slog.String("key", key))
-		return nil, err
+		return nil, c.fail(err, "unable to get all fields from the hash", slog.String("key", key))
 	}
 
 	return result, nil
@@ -155,8 +309,8 @@ func (c *cacheClient) HGetAll(ctx context.Context, key string) (map[string]strin
 
 func (c *cacheClient) HIncrBy(ctx context.Context, key, field string, incr int64) error {
 	if _, err := c.rdb.HIncrBy(ctx, key, field, incr).Result(); err != nil {
-		c.log.Error("unable to increment field in hash in the cache", slog.String("key", key), slog.String("field", field))
-		return err
+		return c.fail(err, "unable to increment field in hash in the cache",
+			slog.String("key", key), slog.String("field", field))
 	}
 
 	return nil
@@ -165,8 +319,7 @@ func (c *cacheClient) HIncrBy(ctx context.Context, key, field string, incr int64
 // List commands
 func (c *cacheClient) LPush(ctx context.Context, key string, value interface{}) error {
 	if err := c.rdb.LPush(ctx, key, value).Err(); err != nil {
-		c.log.Error("unable to lpush key in the cache", slog.String("key", key))
-		return err
+		return c.fail(err, "unable to lpush key in the cache", slog.String("key", key))
 	}
 
 	return nil
@@ -175,8 +328,7 @@ func (c *cacheClient) LPush(ctx context.Context, key string, value interface{})
 func (c *cacheClient) LPushAll(ctx context.Context, key string, values ...interface{}) (int64, error) {
 	val, err := c.rdb.LPush(ctx, key, values...).Result()
 	if err != nil {
-		c.log.Error("unable to LPushAll key in the cache", slog.String("key", key))
-		return 0, err
+		return 0, c.fail(err, "unable to LPushAll key in the cache", slog.String("key", key))
 	}
 
 	return val, nil
@@ -185,11 +337,7 @@ func (c *cacheClient) LPushAll(ctx context.Context, key string, values ...interf
 func (c *cacheClient) LPop(ctx context.Context, key string) (string, error) {
 	val, err := c.rdb.LPop(ctx, key).Result()
 	if err != nil {
-		if err.Error() == redis.Nil.Error() {
-			return "", ErrKeyNotFound
-		}
-		c.log.Error("unable to lpop key in the cache", slog.String("key", key))
-		return "", err
+		return "", c.fail(err, "unable to lpop key in the cache", slog.String("key", key))
 	}
 
 	return val, nil
@@ -198,11 +346,7 @@ func (c *cacheClient) LPop(ctx context.Context, key string) (string, error) {
 func (c *cacheClient) RPop(ctx context.Context, key string) (string, error) {
 	val, err := c.rdb.RPop(ctx, key).Result()
 	if err != nil {
-		if err.Error() == redis.Nil.Error() {
-			return "", ErrKeyNotFound
-		}
-		c.log.Error("unable to rpop key in the cache", slog.String("key", key))
-		return "", err
+		return "", c.fail(err, "unable to rpop key in the cache", slog.String("key", key))
 	}
 
 	return val, nil
@@ -210,8 +354,7 @@ func (c *cacheClient) RPop(ctx context.Context, key string) (string, error) {
 
 func (c *cacheClient) LTrim(ctx context.Context, key string, start, stop int64) error {
 	if err := c.rdb.LTrim(ctx, key, start, stop).Err(); err != nil {
-		c.log.Error("unable to ltrim key in the cache", slog.String("key", key))
-		return err
+		return c.fail(err, "unable to ltrim key in the cache", slog.String("key", key))
 	}
 
 	return nil
@@ -220,8 +363,7 @@ func (c *cacheClient) LTrim(ctx context.Context, key string, start, stop int64)
 func (c *cacheClient) LLen(ctx context.Context, key string) (int64, error) {
 	val, err := c.rdb.LLen(ctx, key).Result()
 	if err != nil {
-		c.log.Error("unable to llen key in the cache", slog.String("key", key))
-		return 0, err
+		return 0, c.fail(err, "unable to llen key in the cache", slog.String("key", key))
 	}
 
 	return val, nil
@@ -230,8 +372,7 @@ func (c *cacheClient) LLen(ctx context.Context, key string) (int64, error) {
 func (c *cacheClient) LRange(ctx context.Context, key string) ([]string, error) {
 	val, err := c.rdb.LRange(ctx, key, 0, -1).Result()
 	if err != nil {
-		c.log.Error("unable to lrange key in the cache", slog.String("key", key))
-		return nil, err
+		return nil, c.fail(err, "unable to lrange key in the cache", slog.String("key", key))
 	}
 
 	return val, nil
@@ -242,8 +383,7 @@ func (c *cacheClient) LRange(ctx context.Context, key string) ([]string, error)
 func (c *cacheClient) SAdd(ctx context.Context, key string, value interface{}) (int64, error) {
 	val, err := c.rdb.SAdd(ctx, key, value).Result()
 	if err != nil {
-		c.log.Error("unable to sadd key in the cache", slog.String("key", key))
-		return 0, err
+		return 0, c.fail(err, "unable to sadd key in the cache", slog.String("key", key))
 	}
 
 	return val, nil
@@ -252,8 +392,7 @@ func (c *cacheClient) SAdd(ctx context.Context, key string, value interface{}) (
 func (c *cacheClient) SAddAll(ctx context.Context, key string, values ...interface{}) (int64, error) {
 	val, err := c.rdb.SAdd(ctx, key, values...).Result()
 	if err != nil {
-		c.log.Error("unable to saddAll key in the cache", slog.String("key", key))
-		return 0, err
+		return 0, c.fail(err, "unable to saddAll key in the cache", slog.String("key", key))
 	}
 
 	return val, nil
@@ -262,8 +401,7 @@ func (c *cacheClient) SAddAll(ctx context.Context, key string, values ...interfa
 func (c *cacheClient) SRem(ctx context.Context, key string, value interface{}) (int64, error) {
 	val, err := c.rdb.SRem(ctx, key, value).Result()
 	if err != nil {
-		c.log.Error("unable to SRem key in the cache", slog.String("key", key))
-		return 0, err
+		return 0, c.fail(err, "unable to SRem key in the cache", slog.String("key", key))
 	}
 
 	return val, nil
@@ -272,8 +410,7 @@ func (c *cacheClient) SRem(ctx context.Context, key string, value interface{}) (
 func (c *cacheClient) SCard(ctx context.Context, key string) (int64, error) {
 	val, err := c.rdb.SCard(ctx, key).Result()
 	if err != nil {
-		c.log.Error("unable to scard key in the cache", slog.String("key", key))
-		return 0, err
+		return 0, c.fail(err, "unable to scard key in the cache", slog.String("key", key))
 	}
 
 	return val, nil
@@ -282,8 +419,7 @@ func (c *cacheClient) SCard(ctx context.Context, key string) (int64, error) {
 func (c *cacheClient) SIsMember(ctx context.Context, key string, value interface{}) (bool, error) {
 	val, err := c.rdb.SIsMember(ctx, key, value).Result()
 	if err != nil {
-		c.log.Error("unable to SIsMember key in the cache", slog.String("key", key))
-		return false, err
+		return false, c.fail(err, "unable to SIsMember key in the cache", slog.String("key", key))
 	}
 
 	return val, nil
@@ -292,8 +428,7 @@ func (c *cacheClient) SIsMember(ctx context.Context, key string, value interface
 func (c *cacheClient) SMembers(ctx context.Context, key string) ([]string, error) {
 	values, err := c.rdb.SMembers(ctx, key).Result()
 	if err != nil {
-		c.log.Error("unable to SMembers key in the cache", slog.String("key", key))
-		return nil, err
+		return nil, c.fail(err, "unable to SMembers key in the cache", slog.String("key", key))
 	}
 
 	return values, nil
@@ -305,8 +440,7 @@ func (c *cacheClient) ZAdd(ctx context.Context, key string, value interface{}) e
 		Score:  float64(time.Now().UnixMilli()),
 		Member: value,
 	}).Err(); err != nil {
-		c.log.Error("unable to zadd key in the cache", slog.String("key", key))
-		return err
+		return c.fail(err, "unable to zadd key in the cache", slog.String("key", key))
 	}
 
 	return nil
@@ -317,8 +451,7 @@ func (c *cacheClient) ZAddWithScore(ctx context.Context, key string, score float
 		Score:  score,
 		Member: value,
 	}).Err(); err != nil {
-		c.log.Error("unable to ZAddWithScore key in the cache", slog.String("key", key))
-		return err
+		return c.fail(err, "unable to ZAddWithScore key in the cache", slog.String("key", key))
 	}
 
 	return nil
@@ -327,8 +460,7 @@ func (c *cacheClient) ZAddWithScore(ctx context.Context, key string, score float
 func (c *cacheClient) ZRem(ctx context.Context, key string, value interface{}) (int64, error) {
 	val, err := c.rdb.ZRem(ctx, key, value).Result()
 	if err != nil {
-		c.log.Error("unable to ZRem key in the cache", slog.String("key", key))
-		return 0, err
+		return 0, c.fail(err, "unable to ZRem key in the cache", slog.String("key", key))
 	}
 
 	return val, nil
@@ -337,22 +469,16 @@ func (c *cacheClient) ZRem(ctx context.Context, key string, value interface{}) (
 func (c *cacheClient) ZPopMin(ctx context.Context, key string, nb int64) ([]string, error) {
 	val, err := c.rdb.ZPopMin(ctx, key, nb).Result()
 	if err != nil {
-		c.log.Error("unable to zpopmin key in the cache", slog.String("key", key))
-		return nil, err
-	}
-	var members []string
-	for _, member := range val {
-		members = append(members, member.Member.(string))
+		return nil, c.fail(err, "unable to zpopmin key in the cache", slog.String("key", key))
 	}
 
-	return members, nil
+	return zMembers(val), nil
 }
 
 func (c *cacheClient) ZCount(ctx context.Context, key string) (int64, error) {
 	val, err := c.rdb.ZCount(ctx, key, "-inf", "+inf").Result()
 	if err != nil {
-		c.log.Error("unable to zcount key in the cache", slog.String("key", key))
-		return 0, err
+		return 0, c.fail(err, "unable to zcount key in the cache", slog.String("key", key))
 	}
 
 	return val, nil
@@ -361,8 +487,7 @@ func (c *cacheClient) ZCount(ctx context.Context, key string) (int64, error) {
 func (c *cacheClient) ZRange(ctx context.Context, key string) ([]string, error) {
 	val, err := c.rdb.ZRange(ctx, key, 0, -1).Result()
 	if err != nil {
-		c.log.Error("unable to zrange key in the cache", slog.String("key", key))
-		return nil, err
+		return nil, c.fail(err, "unable to zrange key in the cache", slog.String("key", key))
 	}
 
 	return val, nil
@@ -371,9 +496,30 @@ func (c *cacheClient) ZRange(ctx context.Context, key string) ([]string, error)
 // Connection management
 func (c *cacheClient) Ping(ctx context.Context) error {
 	if err := c.rdb.Ping(ctx).Err(); err != nil {
-		c.log.Error("unable to ping redis", sl.Err(err))
-		return err
+		return c.fail(err, "unable to ping redis")
 	}
 
 	return nil
 }
+
+// Pub/Sub commands
+func (c *cacheClient) Publish(ctx context.Context, channel string, message interface{}) error {
+	if err := c.rdb.Publish(ctx, channel, message).Err(); err != nil {
+		return c.fail(err, "unable to publish message", slog.String("channel", channel))
+	}
+
+	return nil
+}
+
+func (c *cacheClient) Subscribe(ctx context.Context, channels ...string) (cache.Subscription, error) {
+	return c.subscribe(ctx, c.client.Subscribe(ctx, channels...), channels)
+}
+
+func (c *cacheClient) PSubscribe(ctx context.Context, patterns ...string) (cache.Subscription, error) {
+	return c.subscribe(ctx, c.client.PSubscribe(ctx, patterns...), patterns)
+}
+
+// Scripter returns the underlying client for running Lua scripts, e.g. by the ratelimit package.
+func (c *cacheClient) Scripter() redis.Scripter {
+	return c.rdb
+}
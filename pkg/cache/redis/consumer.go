@@ -0,0 +1,233 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/8thgencore/microservice-common/pkg/logger/sl"
+)
+
+const (
+	defaultConsumerBatchSize     = 10
+	defaultConsumerBlock         = 2 * time.Second
+	defaultConsumerMaxDeliveries = 5
+	defaultConsumerClaimMinIdle  = 30 * time.Second
+	consumerErrorDelay           = time.Second
+)
+
+// StreamHandler processes a stream message. The message is acknowledged if it returns nil
+// and redelivered later otherwise.
+type StreamHandler func(ctx context.Context, msg StreamMessage) error
+
+// ConsumerOption configures Consumer.
+type ConsumerOption func(o *consumerOptions)
+
+type consumerOptions struct {
+	batchSize     int64
+	block         time.Duration
+	maxDeliveries int64
+	claimMinIdle  time.Duration
+	deadLetter    string
+	start         string
+}
+
+// WithBatchSize sets the maximum number of messages read at once.
+func WithBatchSize(n int64) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.batchSize = n
+	}
+}
+
+// WithBlock sets how long a read waits for new messages.
+func WithBlock(d time.Duration) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.block = d
+	}
+}
+
+// WithMaxDeliveries sets how many times a message is delivered before it is moved to the dead-letter stream.
+func WithMaxDeliveries(n int64) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.maxDeliveries = n
+	}
+}
+
+// WithClaimMinIdle sets how long a message stays pending before it is redelivered.
+// Failed messages and messages of crashed consumers are retried after this delay.
+func WithClaimMinIdle(d time.Duration) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.claimMinIdle = d
+	}
+}
+
+// WithDeadLetterStream sets the stream poison messages are moved to, "<stream>:dlq" by default.
+func WithDeadLetterStream(stream string) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.deadLetter = stream
+	}
+}
+
+// WithStartID sets the id the consumer group starts reading from when it is created,
+// "0" (the whole stream) by default or "$" (only new messages).
+func WithStartID(id string) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.start = id
+	}
+}
+
+// Consumer reads messages of a stream as a member of a consumer group and runs handler per message.
+type Consumer struct {
+	client  *cacheClient
+	stream  string
+	group   string
+	name    string
+	handler StreamHandler
+	opts    consumerOptions
+	// cursor is the id XAUTOCLAIM continues scanning pending messages from.
+	cursor string
+}
+
+// NewConsumer creates Consumer named name of the group reading the stream.
+func (c *cacheClient) NewConsumer(
+	stream, group, name string, handler StreamHandler, opts ...ConsumerOption,
+) *Consumer {
+	o := consumerOptions{
+		batchSize:     defaultConsumerBatchSize,
+		block:         defaultConsumerBlock,
+		maxDeliveries: defaultConsumerMaxDeliveries,
+		claimMinIdle:  defaultConsumerClaimMinIdle,
+		deadLetter:    stream + ":dlq",
+		start:         "0",
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Consumer{
+		client:  c,
+		stream:  stream,
+		group:   group,
+		name:    name,
+		handler: handler,
+		opts:    o,
+		cursor:  "0-0",
+	}
+}
+
+// Run creates the consumer group if needed and processes messages until ctx is done.
+// Pending messages idle longer than the claim min idle, including failed ones, are claimed
+// and retried before new messages are read.
+func (cn *Consumer) Run(ctx context.Context) error {
+	if err := cn.client.XGroupCreate(ctx, cn.stream, cn.group, cn.opts.start); err != nil {
+		return err
+	}
+
+	for ctx.Err() == nil {
+		if err := cn.poll(ctx); err != nil && ctx.Err() == nil {
+			select {
+			case <-ctx.Done():
+			case <-time.After(consumerErrorDelay):
+			}
+		}
+	}
+
+	return nil
+}
+
+// poll processes claimed pending messages and then new ones.
+func (cn *Consumer) poll(ctx context.Context) error {
+	// The cursor is kept between polls, so idle messages behind ones which are not idle yet
+	// are claimed too. It is reset to "0-0" by redis once the whole pending list is scanned.
+	claimed, next, err := cn.client.XAutoClaim(
+		ctx, cn.stream, cn.group, cn.name, cn.opts.claimMinIdle, cn.cursor, cn.opts.batchSize)
+	if err != nil {
+		return err
+	}
+	cn.cursor = next
+	if len(claimed) > 0 {
+		return cn.process(ctx, claimed, true)
+	}
+
+	messages, err := cn.client.XReadGroup(ctx, cn.stream, cn.group, cn.name, cn.opts.batchSize, cn.opts.block)
+	if err != nil {
+		return err
+	}
+
+	return cn.process(ctx, messages, false)
+}
+
+// process handles messages, redelivered messages exceeding max deliveries are moved to the dead-letter stream.
+func (cn *Consumer) process(ctx context.Context, messages []StreamMessage, redelivered bool) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	var deliveries map[string]int64
+	if redelivered {
+		ids := make([]string, 0, len(messages))
+		for _, msg := range messages {
+			ids = append(ids, msg.ID)
+		}
+
+		var err error
+		if deliveries, err = cn.client.XDeliveries(ctx, cn.stream, cn.group, ids...); err != nil {
+			return err
+		}
+	}
+
+	for _, msg := range messages {
+		if ctx.Err() != nil {
+			return nil
+		}
+		// Messages deleted from the stream while pending have no values.
+		if msg.Values == nil {
+			_, _ = cn.client.XAck(ctx, cn.stream, cn.group, msg.ID)
+			continue
+		}
+		if deliveries[msg.ID] > cn.opts.maxDeliveries {
+			cn.deadLetter(ctx, msg, deliveries[msg.ID])
+			continue
+		}
+
+		if err := cn.handle(ctx, msg); err != nil {
+			cn.client.log.Error("unable to handle stream message", slog.String("stream", cn.stream),
+				slog.String("group", cn.group), slog.String("id", msg.ID), sl.Err(err))
+			continue
+		}
+		_, _ = cn.client.XAck(ctx, cn.stream, cn.group, msg.ID)
+	}
+
+	return nil
+}
+
+// handle runs handler converting its panic into an error.
+func (cn *Consumer) handle(ctx context.Context, msg StreamMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic recovered: %v", r)
+		}
+	}()
+
+	return cn.handler(ctx, msg)
+}
+
+// deadLetter moves the message to the dead-letter stream and acknowledges it.
+func (cn *Consumer) deadLetter(ctx context.Context, msg StreamMessage, deliveries int64) {
+	values := make(map[string]interface{}, len(msg.Values)+3)
+	for k, v := range msg.Values {
+		values[k] = v
+	}
+	values["dlq_stream"] = cn.stream
+	values["dlq_id"] = msg.ID
+	values["dlq_deliveries"] = deliveries
+
+	if _, err := cn.client.XAdd(ctx, cn.opts.deadLetter, values); err != nil {
+		return
+	}
+	_, _ = cn.client.XAck(ctx, cn.stream, cn.group, msg.ID)
+
+	cn.client.log.Warn("stream message moved to dead-letter stream", slog.String("stream", cn.stream),
+		slog.String("group", cn.group), slog.String("id", msg.ID), slog.String("dead_letter", cn.opts.deadLetter))
+}
@@ -0,0 +1,57 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+
+	"github.com/8thgencore/microservice-common/pkg/cache"
+	"github.com/8thgencore/microservice-common/pkg/logger/sl"
+	"github.com/redis/go-redis/v9"
+)
+
+// errPoolTimeout is the message of the go-redis pool timeout error, which is not exported.
+const errPoolTimeout = "redis: connection pool timeout"
+
+// fail translates err and logs it unless the key is not found, which is an expected outcome.
+func (c *cacheClient) fail(err error, msg string, attrs ...any) error {
+	err = translate(err)
+	if !errors.Is(err, cache.ErrKeyNotFound) {
+		c.log.Error(msg, append(attrs, sl.Err(err))...)
+	}
+
+	return err
+}
+
+// translate converts go-redis errors to the errors of the cache package.
+func translate(err error) error {
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, redis.Nil):
+		return cache.ErrKeyNotFound
+	case errors.Is(err, redis.TxFailedErr):
+		return cache.ErrTxFailed
+	case redis.HasErrorPrefix(err, "WRONGTYPE"):
+		return wrap(cache.ErrWrongType, err)
+	case redis.HasErrorPrefix(err, "READONLY"):
+		return wrap(cache.ErrReadOnly, err)
+	case errors.Is(err, context.DeadlineExceeded), err.Error() == errPoolTimeout,
+		errors.As(err, &netErr) && netErr.Timeout():
+		return wrap(cache.ErrTimeout, err)
+	case errors.Is(err, redis.ErrClosed), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.ECONNRESET), errors.As(err, &netErr):
+		return wrap(cache.ErrConnection, err)
+	default:
+		return err
+	}
+}
+
+// wrap returns err matching kind with errors.Is.
+func wrap(kind, err error) error {
+	return fmt.Errorf("%w: %w", kind, err)
+}
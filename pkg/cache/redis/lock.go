@@ -0,0 +1,266 @@
+package redis
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrLockNotObtained is returned when the lock is held by someone else.
+	ErrLockNotObtained = errors.New("lock not obtained")
+	// ErrLockNotHeld is returned when the lock has expired or was taken over by someone else.
+	ErrLockNotHeld = errors.New("lock not held")
+)
+
+const (
+	defaultLockMinBackoff = 10 * time.Millisecond
+	defaultLockMaxBackoff = time.Second
+)
+
+// KEYS[1] - lock key, KEYS[2] - fencing counter key; ARGV[1] - owner token, ARGV[2] - ttl in ms.
+var obtainScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0
+`)
+
+// KEYS[1] - lock key; ARGV[1] - owner token, ARGV[2] - ttl in ms.
+var refreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// KEYS[1] - lock key; ARGV[1] - owner token.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// LockOption configures locking.
+type LockOption func(o *lockOptions)
+
+type lockOptions struct {
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	noRenew    bool
+}
+
+// WithLockBackoff sets the bounds of the exponential backoff used by Lock between attempts.
+// Non-positive minBackoff is replaced with the default, maxBackoff is raised to minBackoff if lower.
+func WithLockBackoff(minBackoff, maxBackoff time.Duration) LockOption {
+	return func(o *lockOptions) {
+		o.minBackoff = minBackoff
+		o.maxBackoff = maxBackoff
+	}
+}
+
+// WithoutLockRenewal disables automatic lease extension, the lock expires after its ttl.
+func WithoutLockRenewal() LockOption {
+	return func(o *lockOptions) {
+		o.noRenew = true
+	}
+}
+
+// Lock is a distributed lock held by this process. While the lock is held its lease
+// is extended automatically every ttl/3.
+type Lock struct {
+	client *cacheClient
+	name   string
+	key    string
+	owner  string
+	fence  int64
+	ttl    time.Duration
+
+	lost     chan struct{}
+	lostOnce sync.Once
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// TryLock obtains the lock with the given name once, ErrLockNotObtained is returned if it is held by someone else.
+func (c *cacheClient) TryLock(ctx context.Context, name string, ttl time.Duration, opts ...LockOption) (*Lock, error) {
+	o := newLockOptions(opts)
+
+	return c.tryLock(ctx, name, ttl, o)
+}
+
+// Lock obtains the lock with the given name retrying with exponential backoff until ctx is done.
+func (c *cacheClient) Lock(ctx context.Context, name string, ttl time.Duration, opts ...LockOption) (*Lock, error) {
+	o := newLockOptions(opts)
+
+	backoff := o.minBackoff
+	for {
+		lock, err := c.tryLock(ctx, name, ttl, o)
+		if !errors.Is(err, ErrLockNotObtained) {
+			return lock, err
+		}
+
+		// Full jitter spreads retries of competing replicas.
+		t := time.NewTimer(time.Duration(rand.Int64N(int64(backoff)) + 1)) //nolint:gosec
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+		backoff = min(backoff*2, o.maxBackoff)
+	}
+}
+
+func newLockOptions(opts []LockOption) lockOptions {
+	o := lockOptions{
+		minBackoff: defaultLockMinBackoff,
+		maxBackoff: defaultLockMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.minBackoff <= 0 {
+		o.minBackoff = defaultLockMinBackoff
+	}
+	o.maxBackoff = max(o.maxBackoff, o.minBackoff)
+
+	return o
+}
+
+func (c *cacheClient) tryLock(ctx context.Context, name string, ttl time.Duration, o lockOptions) (*Lock, error) {
+	// Hash tag keeps the lock and its fencing counter in the same cluster slot.
+	key := "lock:{" + name + "}"
+	owner := newLockOwner()
+
+	fence, err := obtainScript.Run(ctx, c.rdb, []string{key, key + ":fence"}, owner, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return nil, c.fail(err, "unable to obtain lock", slog.String("lock", name))
+	}
+	if fence == 0 {
+		return nil, ErrLockNotObtained
+	}
+
+	l := &Lock{
+		client: c,
+		name:   name,
+		key:    key,
+		owner:  owner,
+		fence:  fence,
+		ttl:    ttl,
+		lost:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	var renewCtx context.Context
+	renewCtx, l.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	if o.noRenew {
+		close(l.done)
+	} else {
+		go l.renew(renewCtx)
+	}
+
+	return l, nil
+}
+
+// Name returns the name of the lock.
+func (l *Lock) Name() string {
+	return l.name
+}
+
+// Token returns the fencing token of the lock. Tokens grow monotonically with every
+// obtained lock of the same name, so storage can reject writes of stale lock holders.
+func (l *Lock) Token() int64 {
+	return l.fence
+}
+
+// Lost returns channel which is closed when the lock is no longer held: it was released
+// or its lease could not be extended in time.
+func (l *Lock) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Refresh extends the lease of the lock by its ttl.
+func (l *Lock) Refresh(ctx context.Context) error {
+	ok, err := refreshScript.Run(ctx, l.client.rdb, []string{l.key}, l.owner, l.ttl.Milliseconds()).Int64()
+	if err != nil {
+		return l.client.fail(err, "unable to refresh lock", slog.String("lock", l.name))
+	}
+	if ok == 0 {
+		l.markLost()
+		return ErrLockNotHeld
+	}
+
+	return nil
+}
+
+// Unlock stops lease extension and releases the lock if it is still held by this process.
+func (l *Lock) Unlock(ctx context.Context) error {
+	l.cancel()
+	<-l.done
+
+	ok, err := releaseScript.Run(ctx, l.client.rdb, []string{l.key}, l.owner).Int64()
+	if err != nil {
+		return l.client.fail(err, "unable to release lock", slog.String("lock", l.name))
+	}
+	l.markLost()
+	if ok == 0 {
+		return ErrLockNotHeld
+	}
+
+	return nil
+}
+
+// renew extends the lease every ttl/3 until ctx is done or the lock is lost.
+// Transient errors are retried until the lease would have expired.
+func (l *Lock) renew(ctx context.Context) {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	expiresAt := time.Now().Add(l.ttl)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-l.lost:
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		err := l.Refresh(ctx)
+		switch {
+		case err == nil:
+			expiresAt = now.Add(l.ttl)
+		case errors.Is(err, ErrLockNotHeld):
+			return
+		case ctx.Err() == nil && !time.Now().Before(expiresAt):
+			l.markLost()
+			return
+		}
+	}
+}
+
+func (l *Lock) markLost() {
+	l.lostOnce.Do(func() {
+		close(l.lost)
+	})
+}
+
+// newLockOwner returns random token identifying the lock holder.
+func newLockOwner() string {
+	b := make([]byte, 16)
+	_, _ = crand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,333 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/8thgencore/microservice-common/pkg/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// Pipelines and transactions
+func (c *cacheClient) Pipeline(ctx context.Context, fn func(p cache.Pipeliner) error) error {
+	return c.exec(ctx, c.rdb.Pipeline(), fn)
+}
+
+func (c *cacheClient) Watch(ctx context.Context, keys []string, fn func(tx cache.Tx) error) error {
+	err := c.client.Watch(ctx, func(rtx *redis.Tx) error {
+		return fn(&tx{cacheClient: &cacheClient{rdb: rtx, log: c.log, cluster: c.cluster}, rtx: rtx})
+	}, keys...)
+
+	return translate(err)
+}
+
+// tx runs commands within WATCH, Exec queues them in MULTI/EXEC.
+type tx struct {
+	*cacheClient
+	rtx *redis.Tx
+}
+
+func (t *tx) Exec(ctx context.Context, fn func(p cache.Pipeliner) error) error {
+	return t.exec(ctx, t.rtx.TxPipeline(), fn)
+}
+
+// exec queues commands with fn and executes them returning the first error except not found.
+func (c *cacheClient) exec(ctx context.Context, pipe redis.Pipeliner, fn func(p cache.Pipeliner) error) error {
+	if err := fn(&pipeline{pipe: pipe, cluster: c.cluster}); err != nil {
+		pipe.Discard()
+		return err
+	}
+
+	cmds, err := pipe.Exec(ctx)
+	if errors.Is(err, redis.TxFailedErr) {
+		return cache.ErrTxFailed
+	}
+	for _, cmd := range cmds {
+		if err := cmd.Err(); err != nil && !errors.Is(err, redis.Nil) {
+			return c.fail(err, "unable to execute pipeline", slog.String("command", cmd.Name()))
+		}
+	}
+
+	return nil
+}
+
+// pipeline queues commands into go-redis pipeline wrapping their results.
+type pipeline struct {
+	pipe    redis.Pipeliner
+	cluster bool
+}
+
+// String commands
+func (p *pipeline) Set(ctx context.Context, key string, value interface{}) *cache.Result[struct{}] {
+	return status(p.pipe.Set(ctx, key, value, 0))
+}
+
+func (p *pipeline) SetEx(
+	ctx context.Context, key string, value interface{}, duration time.Duration,
+) *cache.Result[struct{}] {
+	return status(p.pipe.SetEx(ctx, key, value, duration))
+}
+
+func (p *pipeline) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) *cache.Result[bool] {
+	return result(p.pipe.SetNX(ctx, key, value, ttl))
+}
+
+func (p *pipeline) SetXX(ctx context.Context, key string, value interface{}, ttl time.Duration) *cache.Result[bool] {
+	return result(p.pipe.SetXX(ctx, key, value, ttl))
+}
+
+func (p *pipeline) Get(ctx context.Context, key string) *cache.Result[string] {
+	return result(p.pipe.Get(ctx, key))
+}
+
+func (p *pipeline) GetSet(
+	ctx context.Context, key string, value interface{}, ttl time.Duration,
+) *cache.Result[string] {
+	return result(p.pipe.SetArgs(ctx, key, value, getSetArgs(ttl)))
+}
+
+func (p *pipeline) GetDel(ctx context.Context, key string) *cache.Result[string] {
+	return result(p.pipe.GetDel(ctx, key))
+}
+
+func (p *pipeline) GetEx(ctx context.Context, key string, ttl time.Duration) *cache.Result[string] {
+	return result(p.pipe.GetEx(ctx, key, ttl))
+}
+
+func (p *pipeline) Del(ctx context.Context, key string) *cache.Result[struct{}] {
+	return status(p.pipe.Del(ctx, key))
+}
+
+func (p *pipeline) DelAll(ctx context.Context, keys ...string) *cache.Result[struct{}] {
+	if len(keys) == 0 {
+		return cache.NewResult(func() (struct{}, error) { return struct{}{}, nil })
+	}
+	if p.cluster {
+		// Keys are deleted one by one, so the cluster client can route them by hash slots.
+		cmds := make([]redis.Cmder, 0, len(keys))
+		for _, key := range keys {
+			cmds = append(cmds, p.pipe.Del(ctx, key))
+		}
+
+		return cache.NewResult(func() (struct{}, error) {
+			for _, cmd := range cmds {
+				if err := cmd.Err(); err != nil {
+					return struct{}{}, translate(err)
+				}
+			}
+
+			return struct{}{}, nil
+		})
+	}
+
+	return status(p.pipe.Del(ctx, keys...))
+}
+
+func (p *pipeline) Incr(ctx context.Context, key string) *cache.Result[struct{}] {
+	return status(p.pipe.Incr(ctx, key))
+}
+
+func (p *pipeline) Decr(ctx context.Context, key string) *cache.Result[struct{}] {
+	return status(p.pipe.Decr(ctx, key))
+}
+
+// Counters with ttl are run with EVAL, since NOSCRIPT of EVALSHA is known only after execution.
+func (p *pipeline) IncrBy(ctx context.Context, key string, incr int64, ttl time.Duration) *cache.Result[int64] {
+	if ttl <= 0 {
+		return result(p.pipe.IncrBy(ctx, key, incr))
+	}
+	cmd := counterScript.Eval(ctx, p.pipe, []string{key}, "INCRBY", incr, ttlMs(ttl))
+
+	return cache.NewResult(func() (int64, error) {
+		val, err := cmd.Int64()
+
+		return val, translate(err)
+	})
+}
+
+func (p *pipeline) IncrByFloat(
+	ctx context.Context, key string, incr float64, ttl time.Duration,
+) *cache.Result[float64] {
+	if ttl <= 0 {
+		return result(p.pipe.IncrByFloat(ctx, key, incr))
+	}
+	cmd := counterScript.Eval(ctx, p.pipe, []string{key}, "INCRBYFLOAT", incr, ttlMs(ttl))
+
+	return cache.NewResult(func() (float64, error) {
+		val, err := cmd.Float64()
+
+		return val, translate(err)
+	})
+}
+
+func (p *pipeline) DecrBy(ctx context.Context, key string, decr int64, ttl time.Duration) *cache.Result[int64] {
+	if ttl <= 0 {
+		return result(p.pipe.DecrBy(ctx, key, decr))
+	}
+	cmd := counterScript.Eval(ctx, p.pipe, []string{key}, "DECRBY", decr, ttlMs(ttl))
+
+	return cache.NewResult(func() (int64, error) {
+		val, err := cmd.Int64()
+
+		return val, translate(err)
+	})
+}
+
+func (p *pipeline) TTL(ctx context.Context, key string) *cache.Result[time.Duration] {
+	return result(p.pipe.TTL(ctx, key))
+}
+
+func (p *pipeline) Expire(ctx context.Context, key string, duration time.Duration) *cache.Result[struct{}] {
+	return status(p.pipe.Expire(ctx, key, duration))
+}
+
+func (p *pipeline) ExpireAt(ctx context.Context, key string, tm time.Time) *cache.Result[struct{}] {
+	return status(p.pipe.ExpireAt(ctx, key, tm))
+}
+
+// Hash commands
+func (p *pipeline) HSet(ctx context.Context, key, field string, value interface{}) *cache.Result[struct{}] {
+	return status(p.pipe.HSet(ctx, key, field, value))
+}
+
+func (p *pipeline) HGet(ctx context.Context, key, field string) *cache.Result[string] {
+	return result(p.pipe.HGet(ctx, key, field))
+}
+
+func (p *pipeline) HGetAll(ctx context.Context, key string) *cache.Result[map[string]string] {
+	return result(p.pipe.HGetAll(ctx, key))
+}
+
+func (p *pipeline) HIncrBy(ctx context.Context, key, field string, incr int64) *cache.Result[struct{}] {
+	return status(p.pipe.HIncrBy(ctx, key, field, incr))
+}
+
+// List commands
+func (p *pipeline) LPush(ctx context.Context, key string, value interface{}) *cache.Result[struct{}] {
+	return status(p.pipe.LPush(ctx, key, value))
+}
+
+func (p *pipeline) LPushAll(ctx context.Context, key string, values ...interface{}) *cache.Result[int64] {
+	return result(p.pipe.LPush(ctx, key, values...))
+}
+
+func (p *pipeline) LPop(ctx context.Context, key string) *cache.Result[string] {
+	return result(p.pipe.LPop(ctx, key))
+}
+
+func (p *pipeline) RPop(ctx context.Context, key string) *cache.Result[string] {
+	return result(p.pipe.RPop(ctx, key))
+}
+
+func (p *pipeline) LTrim(ctx context.Context, key string, start, stop int64) *cache.Result[struct{}] {
+	return status(p.pipe.LTrim(ctx, key, start, stop))
+}
+
+func (p *pipeline) LLen(ctx context.Context, key string) *cache.Result[int64] {
+	return result(p.pipe.LLen(ctx, key))
+}
+
+func (p *pipeline) LRange(ctx context.Context, key string) *cache.Result[[]string] {
+	return result(p.pipe.LRange(ctx, key, 0, -1))
+}
+
+// Set commands
+func (p *pipeline) SAdd(ctx context.Context, key string, value interface{}) *cache.Result[int64] {
+	return result(p.pipe.SAdd(ctx, key, value))
+}
+
+func (p *pipeline) SAddAll(ctx context.Context, key string, values ...interface{}) *cache.Result[int64] {
+	return result(p.pipe.SAdd(ctx, key, values...))
+}
+
+func (p *pipeline) SRem(ctx context.Context, key string, value interface{}) *cache.Result[int64] {
+	return result(p.pipe.SRem(ctx, key, value))
+}
+
+func (p *pipeline) SCard(ctx context.Context, key string) *cache.Result[int64] {
+	return result(p.pipe.SCard(ctx, key))
+}
+
+func (p *pipeline) SIsMember(ctx context.Context, key string, value interface{}) *cache.Result[bool] {
+	return result(p.pipe.SIsMember(ctx, key, value))
+}
+
+func (p *pipeline) SMembers(ctx context.Context, key string) *cache.Result[[]string] {
+	return result(p.pipe.SMembers(ctx, key))
+}
+
+// Sorted Set commands
+func (p *pipeline) ZAdd(ctx context.Context, key string, value interface{}) *cache.Result[struct{}] {
+	return status(p.pipe.ZAdd(ctx, key, redis.Z{
+		Score:  float64(time.Now().UnixMilli()),
+		Member: value,
+	}))
+}
+
+func (p *pipeline) ZAddWithScore(
+	ctx context.Context, key string, score float64, value interface{},
+) *cache.Result[struct{}] {
+	return status(p.pipe.ZAdd(ctx, key, redis.Z{
+		Score:  score,
+		Member: value,
+	}))
+}
+
+func (p *pipeline) ZRem(ctx context.Context, key string, value interface{}) *cache.Result[int64] {
+	return result(p.pipe.ZRem(ctx, key, value))
+}
+
+func (p *pipeline) ZPopMin(ctx context.Context, key string, count int64) *cache.Result[[]string] {
+	cmd := p.pipe.ZPopMin(ctx, key, count)
+
+	return cache.NewResult(func() ([]string, error) {
+		val, err := cmd.Result()
+		if err != nil {
+			return nil, translate(err)
+		}
+
+		return zMembers(val), nil
+	})
+}
+
+func (p *pipeline) ZCount(ctx context.Context, key string) *cache.Result[int64] {
+	return result(p.pipe.ZCount(ctx, key, "-inf", "+inf"))
+}
+
+func (p *pipeline) ZRange(ctx context.Context, key string) *cache.Result[[]string] {
+	return result(p.pipe.ZRange(ctx, key, 0, -1))
+}
+
+// Pub/Sub commands
+func (p *pipeline) Publish(ctx context.Context, channel string, message interface{}) *cache.Result[struct{}] {
+	return status(p.pipe.Publish(ctx, channel, message))
+}
+
+// result wraps the value of the go-redis command translating its error.
+func result[T any](cmd interface{ Result() (T, error) }) *cache.Result[T] {
+	return cache.NewResult(func() (T, error) {
+		val, err := cmd.Result()
+
+		return val, translate(err)
+	})
+}
+
+// status wraps the go-redis command whose value is discarded.
+func status(cmd redis.Cmder) *cache.Result[struct{}] {
+	return cache.NewResult(func() (struct{}, error) {
+		return struct{}{}, translate(cmd.Err())
+	})
+}
+
+// zMembers returns members of the sorted set entries.
+func zMembers(entries []redis.Z) []string {
+	members := make([]string, 0, len(entries))
+	for _, z := range entries {
+		members = append(members, fmt.Sprint(z.Member))
+	}
+
+	return members
+}
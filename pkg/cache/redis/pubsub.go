@@ -0,0 +1,89 @@
+package redis
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/8thgencore/microservice-common/pkg/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// subscription translates messages of go-redis PubSub, which reconnects and resubscribes
+// automatically, to cache.Message. Confirmations of resubscription are sent as markers.
+type subscription struct {
+	pubsub *redis.PubSub
+	ch     chan cache.Message
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+// subscribe waits for the subscription confirmations, so connection errors are reported to the caller
+// and later confirmations are the ones of resubscription.
+func (c *cacheClient) subscribe(
+	ctx context.Context, pubsub *redis.PubSub, channels []string,
+) (cache.Subscription, error) {
+	// Redis confirms every channel of the command before sending any message to them.
+	for range channels {
+		if _, err := pubsub.Receive(ctx); err != nil {
+			_ = pubsub.Close()
+			return nil, c.fail(err, "unable to subscribe to channels", slog.Any("channels", channels))
+		}
+	}
+
+	s := &subscription{
+		pubsub: pubsub,
+		ch:     make(chan cache.Message),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+
+	return s, nil
+}
+
+func (s *subscription) run() {
+	defer close(s.done)
+	defer close(s.ch)
+
+	for msg := range s.pubsub.ChannelWithSubscriptions() {
+		var m cache.Message
+		switch msg := msg.(type) {
+		case *redis.Message:
+			m = cache.Message{Channel: msg.Channel, Pattern: msg.Pattern, Payload: msg.Payload}
+		case *redis.Subscription:
+			switch msg.Kind {
+			case "subscribe":
+				m = cache.Message{Channel: msg.Channel, Resubscribed: true}
+			case "psubscribe":
+				m = cache.Message{Pattern: msg.Channel, Resubscribed: true}
+			default:
+				continue
+			}
+		default:
+			continue
+		}
+
+		select {
+		case s.ch <- m:
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *subscription) Channel() <-chan cache.Message {
+	return s.ch
+}
+
+func (s *subscription) Close() error {
+	var err error
+	s.once.Do(func() {
+		close(s.stop)
+		err = s.pubsub.Close()
+		<-s.done
+	})
+
+	return err
+}
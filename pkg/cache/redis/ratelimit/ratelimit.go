@@ -0,0 +1,308 @@
+// Package ratelimit provides distributed rate limiters backed by redis. Every check is a single
+// atomic Lua script, so limits are shared across replicas without races.
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultPrefix is the prefix of keys used by limiters by default.
+const DefaultPrefix = "ratelimit:"
+
+var (
+	// ErrInvalidCost is returned when the cost of a request is not positive.
+	ErrInvalidCost = errors.New("ratelimit: cost must be positive")
+	// ErrInvalidLimit is returned by constructors when the limit, the rate or the burst is not positive.
+	ErrInvalidLimit = errors.New("ratelimit: limit must be positive")
+	// ErrInvalidWindow is returned by constructors when the window or the period is shorter than 1ms,
+	// the resolution of the scripts.
+	ErrInvalidWindow = errors.New("ratelimit: window must be at least 1ms")
+)
+
+// All scripts take time from redis, so replicas with skewed clocks share the same windows,
+// and return {allowed, remaining, retry after ms, reset after ms}.
+
+// KEYS[1] - key prefix; ARGV[1] - limit, ARGV[2] - window in ms, ARGV[3] - cost.
+var fixedWindowScript = redis.NewScript(`
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local limit, window, cost = tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[3])
+local key = KEYS[1] .. ":" .. math.floor(now / window)
+local reset = window - now % window
+local count = tonumber(redis.call("GET", key) or "0")
+if count + cost > limit then
+	return {0, limit - count, reset, reset}
+end
+count = redis.call("INCRBY", key, cost)
+redis.call("PEXPIRE", key, reset)
+return {1, limit - count, 0, reset}
+`)
+
+// KEYS[1] - log key; ARGV[1] - limit, ARGV[2] - window in ms, ARGV[3] - cost, ARGV[4] - unique request id.
+var slidingWindowLogScript = redis.NewScript(`
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local limit, window, cost = tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[3])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+local count = redis.call("ZCARD", KEYS[1])
+if count + cost > limit then
+	local retry = window
+	local idx = count + cost - limit - 1
+	if cost <= limit then
+		local entry = redis.call("ZRANGE", KEYS[1], idx, idx, "WITHSCORES")
+		retry = tonumber(entry[2]) + window - now
+	end
+	local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+	local reset = 0
+	if #oldest > 0 then
+		reset = tonumber(oldest[2]) + window - now
+	end
+	return {0, limit - count, retry, reset}
+end
+for i = 1, cost do
+	redis.call("ZADD", KEYS[1], now, ARGV[4] .. ":" .. i)
+end
+redis.call("PEXPIRE", KEYS[1], window)
+local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+return {1, limit - count - cost, 0, tonumber(oldest[2]) + window - now}
+`)
+
+// KEYS[1] - key; ARGV[1] - emission interval in ms, ARGV[2] - burst, ARGV[3] - cost.
+var gcraScript = redis.NewScript(`
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + tonumber(t[2]) / 1000
+local emission, burst, cost = tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[3])
+local tolerance = emission * burst
+local tat = tonumber(redis.call("GET", KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+local new_tat = tat + emission * cost
+local allow_at = new_tat - tolerance
+if now < allow_at then
+	return {0, math.floor((tolerance - (tat - now)) / emission), math.ceil(allow_at - now), math.ceil(tat - now)}
+end
+redis.call("SET", KEYS[1], string.format("%.3f", new_tat), "PX", math.ceil(new_tat - now))
+return {1, math.floor((tolerance - (new_tat - now)) / emission), 0, math.ceil(new_tat - now)}
+`)
+
+// Result is the outcome of a rate limit check.
+type Result struct {
+	// Allowed reports whether the request is allowed.
+	Allowed bool
+	// Limit is the maximum number of requests allowed at once.
+	Limit int64
+	// Remaining is the number of requests which are still allowed.
+	Remaining int64
+	// RetryAfter is the time after which the denied request may be retried, zero if allowed.
+	RetryAfter time.Duration
+	// ResetAfter is the time after which the limit is fully restored.
+	ResetAfter time.Duration
+}
+
+// Headers returns conventional rate limit headers. The map may be used for HTTP headers
+// as well as for gRPC metadata, e.g. metadata.New(result.Headers()).
+func (r Result) Headers() map[string]string {
+	h := map[string]string{
+		"X-RateLimit-Limit":     strconv.FormatInt(r.Limit, 10),
+		"X-RateLimit-Remaining": strconv.FormatInt(r.Remaining, 10),
+		"X-RateLimit-Reset":     strconv.FormatInt(ceilSeconds(r.ResetAfter), 10),
+	}
+	if !r.Allowed {
+		h["Retry-After"] = strconv.FormatInt(ceilSeconds(r.RetryAfter), 10)
+	}
+
+	return h
+}
+
+// Limiter checks whether requests identified by key are allowed.
+type Limiter interface {
+	// Allow checks a single request.
+	Allow(ctx context.Context, key string) (Result, error)
+	// AllowN checks n requests at once, they are either all allowed or all denied.
+	AllowN(ctx context.Context, key string, n int64) (Result, error)
+}
+
+// Option configures Limiter.
+type Option func(o *options)
+
+type options struct {
+	prefix string
+}
+
+// WithPrefix sets the prefix of redis keys used by the limiter. Limiters with different
+// algorithms or limits must not share a prefix.
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{prefix: DefaultPrefix}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// validate checks the limit and the window passed to a constructor.
+func validate(limit int64, window time.Duration) error {
+	if limit <= 0 {
+		return ErrInvalidLimit
+	}
+	if window < time.Millisecond {
+		return ErrInvalidWindow
+	}
+
+	return nil
+}
+
+// redisKey returns redis key of the limited key. The hash tag keeps keys
+// derived from it by scripts in the same cluster slot.
+func (o options) redisKey(key string) string {
+	return o.prefix + "{" + key + "}"
+}
+
+type fixedWindow struct {
+	client redis.Scripter
+	limit  int64
+	window time.Duration
+	opts   options
+}
+
+// NewFixedWindow creates Limiter allowing limit requests per window aligned to the epoch.
+// It is the cheapest algorithm but allows bursts of up to 2*limit around window boundaries.
+func NewFixedWindow(client redis.Scripter, limit int64, window time.Duration, opts ...Option) (Limiter, error) {
+	if err := validate(limit, window); err != nil {
+		return nil, err
+	}
+
+	return &fixedWindow{client: client, limit: limit, window: window, opts: newOptions(opts)}, nil
+}
+
+func (l *fixedWindow) Allow(ctx context.Context, key string) (Result, error) {
+	return l.AllowN(ctx, key, 1)
+}
+
+func (l *fixedWindow) AllowN(ctx context.Context, key string, n int64) (Result, error) {
+	if n <= 0 {
+		return Result{}, ErrInvalidCost
+	}
+
+	return run(ctx, l.client, fixedWindowScript, l.opts.redisKey(key), l.limit,
+		l.limit, l.window.Milliseconds(), n)
+}
+
+type slidingWindowLog struct {
+	client redis.Scripter
+	limit  int64
+	window time.Duration
+	opts   options
+}
+
+// NewSlidingWindowLog creates Limiter allowing limit requests during any window.
+// It is exact but stores every request of the window in redis.
+func NewSlidingWindowLog(
+	client redis.Scripter, limit int64, window time.Duration, opts ...Option,
+) (Limiter, error) {
+	if err := validate(limit, window); err != nil {
+		return nil, err
+	}
+
+	return &slidingWindowLog{client: client, limit: limit, window: window, opts: newOptions(opts)}, nil
+}
+
+func (l *slidingWindowLog) Allow(ctx context.Context, key string) (Result, error) {
+	return l.AllowN(ctx, key, 1)
+}
+
+func (l *slidingWindowLog) AllowN(ctx context.Context, key string, n int64) (Result, error) {
+	if n <= 0 {
+		return Result{}, ErrInvalidCost
+	}
+
+	return run(ctx, l.client, slidingWindowLogScript, l.opts.redisKey(key), l.limit,
+		l.limit, l.window.Milliseconds(), n, requestID())
+}
+
+type gcra struct {
+	client redis.Scripter
+	rate   int64
+	period time.Duration
+	burst  int64
+	opts   options
+}
+
+// NewGCRA creates token bucket Limiter implemented with the generic cell rate algorithm.
+// It allows rate requests per period on average and bursts of up to burst requests,
+// storing a single value per key.
+func NewGCRA(
+	client redis.Scripter, rate int64, period time.Duration, burst int64, opts ...Option,
+) (Limiter, error) {
+	if err := validate(rate, period); err != nil {
+		return nil, err
+	}
+	if burst <= 0 {
+		return nil, ErrInvalidLimit
+	}
+
+	return &gcra{client: client, rate: rate, period: period, burst: burst, opts: newOptions(opts)}, nil
+}
+
+func (l *gcra) Allow(ctx context.Context, key string) (Result, error) {
+	return l.AllowN(ctx, key, 1)
+}
+
+func (l *gcra) AllowN(ctx context.Context, key string, n int64) (Result, error) {
+	if n <= 0 {
+		return Result{}, ErrInvalidCost
+	}
+	emission := float64(l.period.Microseconds()) / 1000 / float64(l.rate)
+
+	return run(ctx, l.client, gcraScript, l.opts.redisKey(key), l.burst,
+		strconv.FormatFloat(emission, 'f', -1, 64), l.burst, n)
+}
+
+// run executes the limiter script and converts its reply to Result.
+func run(
+	ctx context.Context, client redis.Scripter, script *redis.Script, key string, limit int64, args ...interface{},
+) (Result, error) {
+	reply, err := script.Run(ctx, client, []string{key}, args...).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("ratelimit: unable to check limit of %q: %w", key, err)
+	}
+	if len(reply) != 4 {
+		return Result{}, fmt.Errorf("ratelimit: unexpected reply %v", reply)
+	}
+
+	return Result{
+		Allowed:    reply[0] == 1,
+		Limit:      limit,
+		Remaining:  max(reply[1], 0),
+		RetryAfter: time.Duration(reply[2]) * time.Millisecond,
+		ResetAfter: time.Duration(reply[3]) * time.Millisecond,
+	}, nil
+}
+
+// requestID returns random id distinguishing requests made at the same millisecond.
+func requestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+func ceilSeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,60 @@
+package ratelimit_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/microservice-common/pkg/cache/redis/ratelimit"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestConstructorValidation(t *testing.T) {
+	client := redis.NewClient(&redis.Options{})
+	t.Cleanup(func() { _ = client.Close() })
+
+	fixed := func(limit int64, window time.Duration) error {
+		_, err := ratelimit.NewFixedWindow(client, limit, window)
+
+		return err
+	}
+	sliding := func(limit int64, window time.Duration) error {
+		_, err := ratelimit.NewSlidingWindowLog(client, limit, window)
+
+		return err
+	}
+	gcra := func(burst int64) func(rate int64, period time.Duration) error {
+		return func(rate int64, period time.Duration) error {
+			_, err := ratelimit.NewGCRA(client, rate, period, burst)
+
+			return err
+		}
+	}
+
+	tests := []struct {
+		name    string
+		create  func(limit int64, window time.Duration) error
+		limit   int64
+		window  time.Duration
+		wantErr error
+	}{
+		{name: "fixed window", create: fixed, limit: 10, window: time.Second},
+		{name: "fixed window zero limit", create: fixed, limit: 0, window: time.Second, wantErr: ratelimit.ErrInvalidLimit},
+		{name: "fixed window short", create: fixed, limit: 10, window: time.Microsecond, wantErr: ratelimit.ErrInvalidWindow},
+		{name: "sliding window", create: sliding, limit: 10, window: time.Millisecond},
+		{name: "sliding negative limit", create: sliding, limit: -1, window: time.Second, wantErr: ratelimit.ErrInvalidLimit},
+		{name: "sliding window zero", create: sliding, limit: 10, window: 0, wantErr: ratelimit.ErrInvalidWindow},
+		{name: "gcra", create: gcra(5), limit: 10, window: time.Second},
+		{name: "gcra zero rate", create: gcra(5), limit: 0, window: time.Second, wantErr: ratelimit.ErrInvalidLimit},
+		{name: "gcra short period", create: gcra(5), limit: 10, window: 0, wantErr: ratelimit.ErrInvalidWindow},
+		{name: "gcra zero burst", create: gcra(0), limit: 10, window: time.Second, wantErr: ratelimit.ErrInvalidLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.create(tt.limit, tt.window); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package redis
+
+import (
+	"context"
+	"iter"
+	"log/slog"
+	"strconv"
+	"sync"
+
+	"github.com/8thgencore/microservice-common/pkg/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// Scan commands
+func (c *cacheClient) Scan(ctx context.Context, match string, count int64) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		nodes, err := c.scanNodes(ctx)
+		if err != nil {
+			yield("", c.fail(err, "unable to scan keys in the cache"))
+			return
+		}
+
+		for _, node := range nodes {
+			more, err := scanPages(func(cursor uint64) *redis.ScanCmd {
+				return node.Scan(ctx, cursor, match, count)
+			}, func(keys []string) bool {
+				for _, key := range keys {
+					if !yield(key, nil) {
+						return false
+					}
+				}
+
+				return true
+			})
+			if err != nil {
+				yield("", c.fail(err, "unable to scan keys in the cache"))
+				return
+			}
+			if !more {
+				return
+			}
+		}
+	}
+}
+
+func (c *cacheClient) HScan(ctx context.Context, key, match string, count int64) iter.Seq2[cache.HashField, error] {
+	return func(yield func(cache.HashField, error) bool) {
+		_, err := scanPages(func(cursor uint64) *redis.ScanCmd {
+			return c.rdb.HScan(ctx, key, cursor, match, count)
+		}, func(page []string) bool {
+			// The page is a flat list of fields and their values.
+			for i := 0; i+1 < len(page); i += 2 {
+				if !yield(cache.HashField{Field: page[i], Value: page[i+1]}, nil) {
+					return false
+				}
+			}
+
+			return true
+		})
+		if err != nil {
+			yield(cache.HashField{}, c.fail(err, "unable to hscan key in the cache", slog.String("key", key)))
+		}
+	}
+}
+
+func (c *cacheClient) SScan(ctx context.Context, key, match string, count int64) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		_, err := scanPages(func(cursor uint64) *redis.ScanCmd {
+			return c.rdb.SScan(ctx, key, cursor, match, count)
+		}, func(members []string) bool {
+			for _, member := range members {
+				if !yield(member, nil) {
+					return false
+				}
+			}
+
+			return true
+		})
+		if err != nil {
+			yield("", c.fail(err, "unable to sscan key in the cache", slog.String("key", key)))
+		}
+	}
+}
+
+func (c *cacheClient) ZScan(
+	ctx context.Context, key, match string, count int64,
+) iter.Seq2[cache.ScoredMember, error] {
+	return func(yield func(cache.ScoredMember, error) bool) {
+		var parseErr error
+		_, err := scanPages(func(cursor uint64) *redis.ScanCmd {
+			return c.rdb.ZScan(ctx, key, cursor, match, count)
+		}, func(page []string) bool {
+			// The page is a flat list of members and their scores.
+			for i := 0; i+1 < len(page); i += 2 {
+				score, err := strconv.ParseFloat(page[i+1], 64)
+				if err != nil {
+					parseErr = err
+					return false
+				}
+				if !yield(cache.ScoredMember{Member: page[i], Score: score}, nil) {
+					return false
+				}
+			}
+
+			return true
+		})
+		if err == nil {
+			err = parseErr
+		}
+		if err != nil {
+			yield(cache.ScoredMember{}, c.fail(err, "unable to zscan key in the cache", slog.String("key", key)))
+		}
+	}
+}
+
+// scanNodes returns nodes holding the keys: all masters in cluster, the client itself otherwise.
+func (c *cacheClient) scanNodes(ctx context.Context) ([]redis.Cmdable, error) {
+	cluster, ok := c.rdb.(*redis.ClusterClient)
+	if !ok {
+		return []redis.Cmdable{c.rdb}, nil
+	}
+
+	var (
+		mu    sync.Mutex
+		nodes []redis.Cmdable
+	)
+	err := cluster.ForEachMaster(ctx, func(_ context.Context, node *redis.Client) error {
+		mu.Lock()
+		nodes = append(nodes, node)
+		mu.Unlock()
+
+		return nil
+	})
+
+	return nodes, err
+}
+
+// scanPages passes pages of the SCAN family command to page until the cursor is exhausted.
+// It reports whether the iteration was not stopped by page.
+func scanPages(scan func(cursor uint64) *redis.ScanCmd, page func(elems []string) bool) (bool, error) {
+	var cursor uint64
+	for {
+		elems, next, err := scan(cursor).Result()
+		if err != nil {
+			return false, err
+		}
+		if !page(elems) {
+			return false, nil
+		}
+		if next == 0 {
+			return true, nil
+		}
+		cursor = next
+	}
+}
@@ -0,0 +1,144 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrScriptNotFound is returned when running a script which was not registered.
+	ErrScriptNotFound = errors.New("script not found")
+	// ErrDuplicateScript is returned when a script with the same name is already registered.
+	ErrDuplicateScript = errors.New("script already registered")
+)
+
+// Scripts is a registry of named Lua scripts. Scripts are run with EVALSHA, the script
+// is sent with EVAL if redis does not know it, e.g. after a restart or a failover.
+type Scripts struct {
+	client *cacheClient
+
+	mu      sync.RWMutex
+	scripts map[string]*redis.Script
+}
+
+// NewScripts creates empty registry of scripts run by the client.
+func (c *cacheClient) NewScripts() *Scripts {
+	return &Scripts{
+		client:  c,
+		scripts: make(map[string]*redis.Script),
+	}
+}
+
+// Register adds the Lua script with the given name.
+func (s *Scripts) Register(name, src string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.scripts[name]; ok {
+		return ErrDuplicateScript
+	}
+	s.scripts[name] = redis.NewScript(src)
+
+	return nil
+}
+
+// Load loads all registered scripts into the script cache of redis, so invalid scripts
+// are reported at startup and the first runs do not send the script body.
+func (s *Scripts) Load(ctx context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for name, script := range s.scripts {
+		if err := script.Load(ctx, s.client.rdb).Err(); err != nil {
+			return s.client.fail(err, "unable to load script", slog.String("script", name))
+		}
+	}
+
+	return nil
+}
+
+// Run runs the script with the given name.
+func (s *Scripts) Run(ctx context.Context, name string, keys []string, args ...interface{}) *ScriptResult {
+	s.mu.RLock()
+	script, ok := s.scripts[name]
+	s.mu.RUnlock()
+	if !ok {
+		return &ScriptResult{err: ErrScriptNotFound}
+	}
+
+	// Run falls back to EVAL on NOSCRIPT error of EVALSHA.
+	cmd := script.Run(ctx, s.client.rdb, keys, args...)
+	if err := cmd.Err(); err != nil {
+		_ = s.client.fail(err, "unable to run script", slog.String("script", name))
+	}
+
+	return &ScriptResult{cmd: cmd}
+}
+
+// ScriptResult is the reply of a script. A nil reply, e.g. Lua false, is returned as ErrKeyNotFound,
+// other errors are translated like errors of the client.
+type ScriptResult struct {
+	cmd *redis.Cmd
+	err error
+}
+
+// Err returns the error of the script.
+func (r *ScriptResult) Err() error {
+	_, err := scriptValue(r, (*redis.Cmd).Result)
+
+	return err
+}
+
+// Val returns the raw reply of the script.
+func (r *ScriptResult) Val() (interface{}, error) {
+	return scriptValue(r, (*redis.Cmd).Result)
+}
+
+// Text returns the reply as string.
+func (r *ScriptResult) Text() (string, error) {
+	return scriptValue(r, (*redis.Cmd).Text)
+}
+
+// Int64 returns the reply as int64.
+func (r *ScriptResult) Int64() (int64, error) {
+	return scriptValue(r, (*redis.Cmd).Int64)
+}
+
+// Float64 returns the reply as float64.
+func (r *ScriptResult) Float64() (float64, error) {
+	return scriptValue(r, (*redis.Cmd).Float64)
+}
+
+// Bool returns the reply as bool, e.g. 1 of Lua true is true.
+func (r *ScriptResult) Bool() (bool, error) {
+	return scriptValue(r, (*redis.Cmd).Bool)
+}
+
+// StringSlice returns the reply of a Lua table as []string.
+func (r *ScriptResult) StringSlice() ([]string, error) {
+	return scriptValue(r, (*redis.Cmd).StringSlice)
+}
+
+// Int64Slice returns the reply of a Lua table as []int64.
+func (r *ScriptResult) Int64Slice() ([]int64, error) {
+	return scriptValue(r, (*redis.Cmd).Int64Slice)
+}
+
+// scriptValue converts the reply with fn translating its error.
+func scriptValue[T any](r *ScriptResult, fn func(cmd *redis.Cmd) (T, error)) (T, error) {
+	var zero T
+	if r.err != nil {
+		return zero, r.err
+	}
+
+	val, err := fn(r.cmd)
+	if err != nil {
+		return zero, translate(err)
+	}
+
+	return val, nil
+}
@@ -0,0 +1,138 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// StreamMessage is a message of a redis stream.
+type StreamMessage struct {
+	ID     string
+	Values map[string]interface{}
+}
+
+// Stream commands
+func (c *cacheClient) XAdd(ctx context.Context, stream string, values map[string]interface{}) (string, error) {
+	id, err := c.rdb.XAdd(ctx, &redis.XAddArgs{Stream: stream, Values: values}).Result()
+	if err != nil {
+		return "", c.fail(err, "unable to xadd message to the stream", slog.String("stream", stream))
+	}
+
+	return id, nil
+}
+
+// XGroupCreate creates consumer group reading the stream from start, e.g. "0" or "$".
+// The stream is created if needed, an already existing group is not an error.
+func (c *cacheClient) XGroupCreate(ctx context.Context, stream, group, start string) error {
+	err := c.rdb.XGroupCreateMkStream(ctx, stream, group, start).Err()
+	if err != nil && !redis.HasErrorPrefix(err, "BUSYGROUP") {
+		return c.fail(err, "unable to create consumer group", slog.String("stream", stream),
+			slog.String("group", group))
+	}
+
+	return nil
+}
+
+// XReadGroup reads up to count new messages of the stream for the consumer of the group
+// blocking up to block if there are none. No messages is not an error.
+func (c *cacheClient) XReadGroup(
+	ctx context.Context, stream, group, consumer string, count int64, block time.Duration,
+) ([]StreamMessage, error) {
+	streams, err := c.rdb.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{stream, ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, c.fail(err, "unable to xreadgroup messages from the stream", slog.String("stream", stream),
+			slog.String("group", group))
+	}
+
+	var messages []StreamMessage
+	for _, s := range streams {
+		messages = append(messages, streamMessages(s.Messages)...)
+	}
+
+	return messages, nil
+}
+
+func (c *cacheClient) XAck(ctx context.Context, stream, group string, ids ...string) (int64, error) {
+	val, err := c.rdb.XAck(ctx, stream, group, ids...).Result()
+	if err != nil {
+		return 0, c.fail(err, "unable to xack messages of the stream", slog.String("stream", stream),
+			slog.String("group", group))
+	}
+
+	return val, nil
+}
+
+// XAutoClaim transfers up to count messages pending longer than minIdle to the consumer
+// starting from the start id. It returns the claimed messages and the id to continue from.
+func (c *cacheClient) XAutoClaim(
+	ctx context.Context, stream, group, consumer string, minIdle time.Duration, start string, count int64,
+) ([]StreamMessage, string, error) {
+	messages, next, err := c.rdb.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Start:    start,
+		Count:    count,
+	}).Result()
+	if err != nil {
+		return nil, "", c.fail(err, "unable to xautoclaim messages of the stream", slog.String("stream", stream),
+			slog.String("group", group))
+	}
+
+	return streamMessages(messages), next, nil
+}
+
+// XDeliveries returns delivery counts of the pending messages of the group with the given ids.
+// Every id is looked up separately in a single round trip, ids which are not pending are omitted.
+func (c *cacheClient) XDeliveries(ctx context.Context, stream, group string, ids ...string) (map[string]int64, error) {
+	cmds := make([]*redis.XPendingExtCmd, 0, len(ids))
+	_, err := c.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range ids {
+			cmds = append(cmds, pipe.XPendingExt(ctx, &redis.XPendingExtArgs{
+				Stream: stream,
+				Group:  group,
+				Start:  id,
+				End:    id,
+				Count:  1,
+			}))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, c.fail(err, "unable to xpending messages of the stream", slog.String("stream", stream),
+			slog.String("group", group))
+	}
+
+	deliveries := make(map[string]int64, len(ids))
+	for _, cmd := range cmds {
+		for _, p := range cmd.Val() {
+			deliveries[p.ID] = p.RetryCount
+		}
+	}
+
+	return deliveries, nil
+}
+
+func streamMessages(messages []redis.XMessage) []StreamMessage {
+	result := make([]StreamMessage, 0, len(messages))
+	for _, m := range messages {
+		result = append(result, StreamMessage{ID: m.ID, Values: m.Values})
+	}
+
+	return result
+}
@@ -0,0 +1,44 @@
+package cache
+
+import "context"
+
+// DefaultScanCount is the number of keys fetched and deleted per round trip by DeleteByPattern.
+const DefaultScanCount = 100
+
+// HashField is a field of a hash yielded by HScan.
+type HashField struct {
+	Field string
+	Value string
+}
+
+// ScoredMember is a member of a sorted set yielded by ZScan.
+type ScoredMember struct {
+	Member string
+	Score  float64
+}
+
+// DeleteByPattern deletes keys matching the glob pattern in batches of count keys, DefaultScanCount
+// if count is not positive. Unlike KEYS, Scan does not block redis, but keys created while deleting
+// may be left.
+func DeleteByPattern(ctx context.Context, client Client, match string, count int64) error {
+	if count <= 0 {
+		count = DefaultScanCount
+	}
+
+	batch := make([]string, 0, count)
+	for key, err := range client.Scan(ctx, match, count) {
+		if err != nil {
+			return err
+		}
+		batch = append(batch, key)
+		if int64(len(batch)) < count {
+			continue
+		}
+		if err := client.DelAll(ctx, batch...); err != nil {
+			return err
+		}
+		batch = batch[:0]
+	}
+
+	return client.DelAll(ctx, batch...)
+}
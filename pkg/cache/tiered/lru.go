@@ -0,0 +1,77 @@
+package tiered
+
+import (
+	"container/list"
+	"time"
+)
+
+// entry is a value stored in lru.
+type entry struct {
+	key       string
+	value     string
+	expiresAt time.Time
+}
+
+// lru is a bounded least recently used cache with per-entry TTL. It is not safe for concurrent use.
+type lru struct {
+	size  int
+	order *list.List
+	items map[string]*list.Element
+}
+
+func newLRU(size int) *lru {
+	return &lru{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// get returns the value of the key if it is present and not expired.
+func (l *lru) get(key string, now time.Time) (string, bool) {
+	el, ok := l.items[key]
+	if !ok {
+		return "", false
+	}
+	e := el.Value.(*entry)
+	if !now.Before(e.expiresAt) {
+		l.removeElement(el)
+		return "", false
+	}
+	l.order.MoveToFront(el)
+
+	return e.value, true
+}
+
+// add stores the value of the key for ttl evicting the least recently used entry if the cache is full.
+func (l *lru) add(key, value string, ttl time.Duration, now time.Time) {
+	if el, ok := l.items[key]; ok {
+		e := el.Value.(*entry)
+		e.value, e.expiresAt = value, now.Add(ttl)
+		l.order.MoveToFront(el)
+		return
+	}
+
+	l.items[key] = l.order.PushFront(&entry{key: key, value: value, expiresAt: now.Add(ttl)})
+	if l.order.Len() > l.size {
+		l.removeElement(l.order.Back())
+	}
+}
+
+// remove deletes the key.
+func (l *lru) remove(key string) {
+	if el, ok := l.items[key]; ok {
+		l.removeElement(el)
+	}
+}
+
+// purge deletes all keys.
+func (l *lru) purge() {
+	l.order.Init()
+	l.items = make(map[string]*list.Element)
+}
+
+func (l *lru) removeElement(el *list.Element) {
+	l.order.Remove(el)
+	delete(l.items, el.Value.(*entry).key)
+}
@@ -0,0 +1,299 @@
+// Package tiered provides two-level cache: a bounded in-process LRU in front of cache.Client.
+// Writes and deletes are broadcast to all instances via pub/sub, so local copies are dropped
+// when any replica changes the key.
+package tiered
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/8thgencore/microservice-common/pkg/cache"
+	"github.com/8thgencore/microservice-common/pkg/logger/sl"
+)
+
+const (
+	// DefaultChannel is the pub/sub channel used for invalidations by default.
+	DefaultChannel = "cache:invalidate"
+	// DefaultSize is the default maximum number of keys in the local cache.
+	DefaultSize = 10000
+	// DefaultLocalTTL is the default time a key is kept in the local cache.
+	DefaultLocalTTL = time.Minute
+
+	resubscribeDelay = time.Second
+	// ttlPrecision is the error of TTL which is rounded to the nearest second by redis.
+	ttlPrecision = 500 * time.Millisecond
+)
+
+// Option configures Cache.
+type Option func(c *Cache)
+
+// WithSize sets the maximum number of keys in the local cache.
+func WithSize(size int) Option {
+	return func(c *Cache) {
+		c.size = size
+	}
+}
+
+// WithLocalTTL sets the time a key is kept in the local cache. It bounds staleness
+// of local copies if an invalidation message is lost. Keys expiring sooner in the remote
+// cache are kept locally only until they expire there.
+func WithLocalTTL(ttl time.Duration) Option {
+	return func(c *Cache) {
+		c.localTTL = ttl
+	}
+}
+
+// WithChannel sets the pub/sub channel used for invalidations.
+func WithChannel(channel string) Option {
+	return func(c *Cache) {
+		c.channel = channel
+	}
+}
+
+// WithLogger sets the logger. slog.Default() is used by default.
+func WithLogger(log *slog.Logger) Option {
+	return func(c *Cache) {
+		c.log = log
+	}
+}
+
+// invalidation is a message broadcast to all instances.
+type invalidation struct {
+	Origin string   `json:"origin"`
+	Keys   []string `json:"keys"`
+}
+
+// Cache is a two-level cache. Reads are served from the local LRU if possible,
+// writes go to the remote cache and invalidate local copies on all instances.
+type Cache struct {
+	client   cache.Client
+	log      *slog.Logger
+	id       string
+	channel  string
+	size     int
+	localTTL time.Duration
+
+	mu    sync.Mutex
+	local *lru
+	// gen is incremented on every invalidation, so values read from the remote cache
+	// concurrently with an invalidation are not stored locally.
+	gen uint64
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New creates two-level cache and starts listening to invalidations. Close must be called
+// to stop listening, e.g. by registering it in closer.
+func New(client cache.Client, opts ...Option) *Cache {
+	c := &Cache{
+		client:   client,
+		log:      slog.Default(),
+		id:       newID(),
+		channel:  DefaultChannel,
+		size:     DefaultSize,
+		localTTL: DefaultLocalTTL,
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.local = newLRU(c.size)
+
+	var ctx context.Context
+	ctx, c.cancel = context.WithCancel(context.Background())
+	go c.listen(ctx)
+
+	return c
+}
+
+// Get returns the value of the key from the local cache or from the remote one.
+func (c *Cache) Get(ctx context.Context, key string) (string, error) {
+	c.mu.Lock()
+	val, ok := c.local.get(key, time.Now())
+	gen := c.gen
+	c.mu.Unlock()
+	if ok {
+		return val, nil
+	}
+
+	// TTL is read with the value, so the local copy does not outlive the remote key.
+	var (
+		get *cache.Result[string]
+		ttl *cache.Result[time.Duration]
+	)
+	err := c.client.Pipeline(ctx, func(p cache.Pipeliner) error {
+		get = p.Get(ctx, key)
+		ttl = p.TTL(ctx, key)
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	val, err = get.Result()
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	if localTTL := c.localTTLOf(ttl.Val()); c.gen == gen && localTTL > 0 {
+		c.local.add(key, val, localTTL, time.Now())
+	}
+	c.mu.Unlock()
+
+	return val, nil
+}
+
+// localTTLOf returns how long a value with the remote TTL may be kept locally, not positive
+// if it must not be cached, e.g. the key is about to expire or has been deleted meanwhile.
+func (c *Cache) localTTLOf(remote time.Duration) time.Duration {
+	switch {
+	case remote == -1:
+		// The remote key does not expire.
+		return c.localTTL
+	case remote > 0:
+		return min(c.localTTL, remote-ttlPrecision)
+	default:
+		return 0
+	}
+}
+
+// Set stores the value in the remote cache and invalidates the key on all instances.
+// Zero ttl means the key does not expire.
+func (c *Cache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	var err error
+	if ttl > 0 {
+		err = c.client.SetEx(ctx, key, value, ttl)
+	} else {
+		err = c.client.Set(ctx, key, value)
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.Invalidate(ctx, key)
+}
+
+// Del deletes the keys from the remote cache and invalidates them on all instances.
+func (c *Cache) Del(ctx context.Context, keys ...string) error {
+	if err := c.client.DelAll(ctx, keys...); err != nil {
+		return err
+	}
+
+	return c.Invalidate(ctx, keys...)
+}
+
+// Invalidate drops local copies of the keys on all instances.
+func (c *Cache) Invalidate(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	c.drop(keys)
+
+	msg, err := json.Marshal(invalidation{Origin: c.id, Keys: keys})
+	if err != nil {
+		return err
+	}
+
+	return c.client.Publish(ctx, c.channel, msg)
+}
+
+// Close stops listening to invalidations.
+func (c *Cache) Close() error {
+	c.cancel()
+	<-c.done
+
+	return nil
+}
+
+// drop removes keys from the local cache.
+func (c *Cache) drop(keys []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	for _, key := range keys {
+		c.local.remove(key)
+	}
+}
+
+// purge removes all keys from the local cache.
+func (c *Cache) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	c.local.purge()
+}
+
+// listen applies invalidations of other instances until ctx is done.
+func (c *Cache) listen(ctx context.Context) {
+	defer close(c.done)
+
+	for {
+		if err := c.consume(ctx); err != nil {
+			c.log.Error("unable to listen to cache invalidations", slog.String("channel", c.channel), sl.Err(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(resubscribeDelay):
+		}
+	}
+}
+
+// consume subscribes to invalidations and applies them until ctx is done or the subscription is closed.
+// While not subscribed the instance misses invalidations, so the local cache is purged once
+// the subscription is confirmed and every time it is restored after a lost connection.
+func (c *Cache) consume(ctx context.Context) error {
+	sub, err := c.client.Subscribe(ctx, c.channel)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	c.purge()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-sub.Channel():
+			if !ok {
+				return nil
+			}
+			if msg.Resubscribed {
+				c.purge()
+				continue
+			}
+			c.handle(msg.Payload)
+		}
+	}
+}
+
+// handle applies an invalidation message.
+func (c *Cache) handle(payload string) {
+	var msg invalidation
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		c.log.Error("unable to decode cache invalidation", sl.Err(err))
+		return
+	}
+	if msg.Origin == c.id {
+		return
+	}
+	c.drop(msg.Keys)
+}
+
+// newID returns random identifier of the instance.
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,220 @@
+package tiered_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/microservice-common/pkg/cache"
+	"github.com/8thgencore/microservice-common/pkg/cache/memory"
+	"github.com/8thgencore/microservice-common/pkg/cache/tiered"
+)
+
+// client records when the invalidation subscription is ready and runs afterFetch
+// after a value is read from the remote cache.
+type client struct {
+	cache.Client
+	once       sync.Once
+	ready      chan struct{}
+	afterFetch func()
+}
+
+func (c *client) Subscribe(ctx context.Context, channels ...string) (cache.Subscription, error) {
+	sub, err := c.Client.Subscribe(ctx, channels...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscription{Subscription: sub, client: c}, nil
+}
+
+func (c *client) Pipeline(ctx context.Context, fn func(p cache.Pipeliner) error) error {
+	err := c.Client.Pipeline(ctx, fn)
+	if c.afterFetch != nil {
+		c.afterFetch()
+	}
+
+	return err
+}
+
+// subscription closes ready of the client once the cache waits for messages,
+// i.e. after the local cache has been purged on subscribe.
+type subscription struct {
+	cache.Subscription
+	client *client
+}
+
+func (s *subscription) Channel() <-chan cache.Message {
+	s.client.once.Do(func() { close(s.client.ready) })
+
+	return s.Subscription.Channel()
+}
+
+// newCache returns the tiered cache on top of remote which listens to invalidations.
+func newCache(t *testing.T, remote cache.Client, opts ...tiered.Option) (*tiered.Cache, *client) {
+	t.Helper()
+
+	cl := &client{Client: remote, ready: make(chan struct{})}
+	c := tiered.New(cl, opts...)
+	t.Cleanup(func() { _ = c.Close() })
+
+	select {
+	case <-cl.ready:
+	case <-time.After(time.Second):
+		t.Fatal("cache is not subscribed to invalidations")
+	}
+
+	return c, cl
+}
+
+func get(t *testing.T, c *tiered.Cache, key string) string {
+	t.Helper()
+
+	val, err := c.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Get(%q): %v", key, err)
+	}
+
+	return val
+}
+
+func set(t *testing.T, remote cache.Client, key, value string) {
+	t.Helper()
+
+	if err := remote.Set(context.Background(), key, value); err != nil {
+		t.Fatalf("Set(%q): %v", key, err)
+	}
+}
+
+func TestLocalHit(t *testing.T) {
+	remote := memory.NewClient()
+	c, _ := newCache(t, remote)
+
+	set(t, remote, "key", "old")
+	if got := get(t, c, "key"); got != "old" {
+		t.Fatalf("Get = %q, want %q", got, "old")
+	}
+
+	// The remote cache is changed bypassing the tiered one, so the local copy is not invalidated.
+	set(t, remote, "key", "new")
+	if got := get(t, c, "key"); got != "old" {
+		t.Errorf("Get = %q, want local copy %q", got, "old")
+	}
+}
+
+func TestInvalidation(t *testing.T) {
+	ctx := context.Background()
+	remote := memory.NewClient()
+	a, _ := newCache(t, remote)
+	b, _ := newCache(t, remote)
+
+	set(t, remote, "key", "old")
+	if got := get(t, b, "key"); got != "old" {
+		t.Fatalf("Get = %q, want %q", got, "old")
+	}
+
+	tests := []struct {
+		name   string
+		change func() error
+		want   func(val string, err error) bool
+	}{
+		{
+			name:   "set",
+			change: func() error { return a.Set(ctx, "key", "new", 0) },
+			want:   func(val string, err error) bool { return err == nil && val == "new" },
+		},
+		{
+			name:   "del",
+			change: func() error { return a.Del(ctx, "key") },
+			want:   func(_ string, err error) bool { return errors.Is(err, cache.ErrKeyNotFound) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.change(); err != nil {
+				t.Fatalf("change: %v", err)
+			}
+
+			// The invalidation is delivered asynchronously.
+			deadline := time.Now().Add(time.Second)
+			for {
+				val, err := b.Get(ctx, "key")
+				if tt.want(val, err) {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("Get = %q, %v after invalidation from another instance", val, err)
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestEviction(t *testing.T) {
+	remote := memory.NewClient()
+	c, _ := newCache(t, remote, tiered.WithSize(2))
+
+	for _, key := range []string{"a", "b", "c"} {
+		set(t, remote, key, "old")
+		get(t, c, key)
+	}
+	for _, key := range []string{"a", "b", "c"} {
+		set(t, remote, key, "new")
+	}
+
+	// "a" is the least recently used key evicted by "c".
+	if got := get(t, c, "a"); got != "new" {
+		t.Errorf("Get(a) = %q, want evicted key read from remote", got)
+	}
+	if got := get(t, c, "c"); got != "old" {
+		t.Errorf("Get(c) = %q, want local copy", got)
+	}
+}
+
+func TestLocalTTLCappedByRemote(t *testing.T) {
+	ctx := context.Background()
+	remote := memory.NewClient()
+	c, _ := newCache(t, remote, tiered.WithLocalTTL(time.Hour))
+
+	if err := remote.SetEx(ctx, "key", "value", time.Second); err != nil {
+		t.Fatalf("SetEx: %v", err)
+	}
+	if got := get(t, c, "key"); got != "value" {
+		t.Fatalf("Get = %q, want %q", got, "value")
+	}
+
+	time.Sleep(1100 * time.Millisecond)
+
+	if val, err := c.Get(ctx, "key"); !errors.Is(err, cache.ErrKeyNotFound) {
+		t.Errorf("Get = %q, %v after the remote key expired, want %v", val, err, cache.ErrKeyNotFound)
+	}
+}
+
+func TestConcurrentInvalidation(t *testing.T) {
+	ctx := context.Background()
+	remote := memory.NewClient()
+	c, cl := newCache(t, remote)
+
+	set(t, remote, "key", "old")
+
+	// The key is invalidated after the value is read from the remote cache but before
+	// it is stored locally, so the stale value must not be cached.
+	cl.afterFetch = func() {
+		cl.afterFetch = nil
+		set(t, remote, "key", "new")
+		if err := c.Invalidate(ctx, "key"); err != nil {
+			t.Errorf("Invalidate: %v", err)
+		}
+	}
+	if got := get(t, c, "key"); got != "old" {
+		t.Fatalf("Get = %q, want %q", got, "old")
+	}
+
+	if got := get(t, c, "key"); got != "new" {
+		t.Errorf("Get = %q, want %q, the value read concurrently with invalidation is cached", got, "new")
+	}
+}
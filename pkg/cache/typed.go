@@ -0,0 +1,57 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/8thgencore/microservice-common/pkg/cache/codec"
+)
+
+// Typed stores values of type T in the cache encoded with the codec.
+type Typed[T any] struct {
+	client Client
+	codec  codec.Codec
+}
+
+// NewTyped creates typed wrapper over the cache client. codec.JSON is used if c is nil.
+func NewTyped[T any](client Client, c codec.Codec) *Typed[T] {
+	if c == nil {
+		c = codec.JSON{}
+	}
+
+	return &Typed[T]{client: client, codec: c}
+}
+
+// Get returns decoded value of the key. Errors of the client, e.g. key not found, are returned as is.
+func (t *Typed[T]) Get(ctx context.Context, key string) (T, error) {
+	var value T
+
+	data, err := t.client.Get(ctx, key)
+	if err != nil {
+		return value, err
+	}
+	if err := t.codec.Unmarshal([]byte(data), &value); err != nil {
+		return value, fmt.Errorf("unable to decode cache value of key %q: %w", key, err)
+	}
+
+	return value, nil
+}
+
+// Set stores encoded value of the key. Zero ttl means the key does not expire.
+func (t *Typed[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	data, err := t.codec.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("unable to encode cache value of key %q: %w", key, err)
+	}
+	if ttl > 0 {
+		return t.client.SetEx(ctx, key, data, ttl)
+	}
+
+	return t.client.Set(ctx, key, data)
+}
+
+// Del deletes the key.
+func (t *Typed[T]) Del(ctx context.Context, key string) error {
+	return t.client.Del(ctx, key)
+}
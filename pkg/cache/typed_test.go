@@ -0,0 +1,88 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/microservice-common/pkg/cache"
+	"github.com/8thgencore/microservice-common/pkg/cache/codec"
+	"github.com/8thgencore/microservice-common/pkg/cache/memory"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type user struct {
+	ID   int
+	Name string
+	Tags []string
+}
+
+// roundTrip stores the value through Typed with the codec and reads it back.
+func roundTrip[T any](t *testing.T, c codec.Codec, value T, equal func(a, b T) bool) {
+	t.Helper()
+
+	ctx := context.Background()
+	typed := cache.NewTyped[T](memory.NewClient(), c)
+
+	if err := typed.Set(ctx, "key", value, time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	got, err := typed.Get(ctx, "key")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !equal(got, value) {
+		t.Errorf("Get = %+v, want %+v", got, value)
+	}
+
+	if _, err := typed.Get(ctx, "missing"); !errors.Is(err, cache.ErrKeyNotFound) {
+		t.Errorf("Get of missing key error = %v, want %v", err, cache.ErrKeyNotFound)
+	}
+}
+
+func TestTyped(t *testing.T) {
+	value := user{ID: 42, Name: "gopher", Tags: []string{"a", "b"}}
+
+	tests := []struct {
+		name  string
+		codec codec.Codec
+	}{
+		{name: "default", codec: nil},
+		{name: "json", codec: codec.JSON{}},
+		{name: "gob", codec: codec.Gob{}},
+		{name: "msgpack", codec: codec.Msgpack{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roundTrip(t, tt.codec, value, func(a, b user) bool { return reflect.DeepEqual(a, b) })
+		})
+	}
+
+	t.Run("protobuf", func(t *testing.T) {
+		// T is a pointer, so Get decodes into a pointer to a nil message.
+		roundTrip(t, codec.Protobuf{}, wrapperspb.String("gopher"), func(a, b *wrapperspb.StringValue) bool {
+			return proto.Equal(a, b)
+		})
+	})
+}
+
+func TestTypedNotProtoMessage(t *testing.T) {
+	ctx := context.Background()
+	client := memory.NewClient()
+	typed := cache.NewTyped[user](client, codec.Protobuf{})
+
+	if err := typed.Set(ctx, "key", user{ID: 42}, 0); !errors.Is(err, codec.ErrNotProtoMessage) {
+		t.Errorf("Set error = %v, want %v", err, codec.ErrNotProtoMessage)
+	}
+
+	if err := client.Set(ctx, "key", "value"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if _, err := typed.Get(ctx, "key"); !errors.Is(err, codec.ErrNotProtoMessage) {
+		t.Errorf("Get error = %v, want %v", err, codec.ErrNotProtoMessage)
+	}
+}
@@ -1,88 +1,643 @@
 package closer
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
+	"runtime/debug"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/8thgencore/microservice-common/pkg/logger/sl"
+)
+
+const (
+	// DefaultPhase is the name of the phase used by Add and AddContext.
+	DefaultPhase = "default"
+	// PreShutdownPhase is the name of the phase used by AddPreShutdown.
+	PreShutdownPhase = "pre-shutdown"
+	// ComponentPhase is the name of the phase used by Runner. It is executed before all named phases,
+	// so components such as servers are stopped before the resources they use are closed.
+	ComponentPhase = "components"
 )
 
-var globalCloser = New()
+// ErrTimeout is returned for closer functions which did not finish before the deadline.
+var ErrTimeout = errors.New("closer: timed out")
+
+var globalCloser atomic.Pointer[Closer]
+
+func init() {
+	globalCloser.Store(New())
+}
+
+// Global returns the globalCloser used by package-level functions.
+func Global() *Closer {
+	return globalCloser.Load()
+}
+
+// SetGlobal replaces the globalCloser and returns func restoring the previous one,
+// e.g. `defer closer.SetGlobal(closer.NewWithOptions())()` in tests.
+func SetGlobal(c *Closer) (restore func()) {
+	prev := globalCloser.Swap(c)
+
+	return func() {
+		globalCloser.Store(prev)
+	}
+}
+
+// ResetGlobal replaces the globalCloser with a fresh one without signal handling.
+func ResetGlobal() {
+	globalCloser.Store(New())
+}
+
+// PanicError is returned for closer functions which panicked. Panics are recovered per closer,
+// so the remaining resources are still closed.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+// Error implements error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("closer: panic: %v", e.Value)
+}
 
 // Add adds `func() error` callback to the globalCloser.
 func Add(f ...func() error) {
-	globalCloser.Add(f...)
+	Global().Add(f...)
+}
+
+// AddContext adds `func(ctx context.Context) error` callback to the globalCloser.
+func AddContext(f ...func(ctx context.Context) error) {
+	Global().AddContext(f...)
+}
+
+// AddNamed adds named `func() error` callback to the globalCloser.
+func AddNamed(name string, f ...func() error) {
+	Global().AddNamed(name, f...)
+}
+
+// AddNamedContext adds named `func(ctx context.Context) error` callback to the globalCloser.
+func AddNamedContext(name string, f ...func(ctx context.Context) error) {
+	Global().AddNamedContext(name, f...)
+}
+
+// AddPhase adds `func() error` callback to the named phase of the globalCloser.
+func AddPhase(name string, f ...func() error) {
+	Global().AddPhase(name, f...)
+}
+
+// AddPreShutdown adds pre-shutdown hook to the globalCloser.
+func AddPreShutdown(f ...func(ctx context.Context) error) {
+	Global().AddPreShutdown(f...)
+}
+
+// IsShuttingDown reports whether shutdown of the globalCloser has started.
+func IsShuttingDown() bool {
+	return Global().IsShuttingDown()
+}
+
+// Context returns the context of the globalCloser which is cancelled when shutdown starts.
+func Context() context.Context {
+	return Global().Context()
 }
 
 // Wait callback to the globalCloser.
 func Wait() {
-	globalCloser.Wait()
+	Global().Wait()
+}
+
+// WaitErr callback to the globalCloser.
+func WaitErr() error {
+	return Global().WaitErr()
 }
 
 // CloseAll callback to the globalCloser
 func CloseAll() {
-	globalCloser.CloseAll()
+	Global().CloseAll()
+}
+
+// CloseAllErr callback to the globalCloser.
+func CloseAllErr() error {
+	return Global().CloseAllErr()
+}
+
+// Option configures Closer.
+type Option func(c *Closer)
+
+// WithSignals makes Closer call CloseAll automatically when one of signals is received from OS.
+func WithSignals(sig ...os.Signal) Option {
+	return func(c *Closer) {
+		c.signals = sig
+	}
+}
+
+// WithSignalChannel makes Closer listen to the given channel instead of OS signals.
+// It allows to trigger shutdown and forced exit deterministically, e.g. in tests.
+func WithSignalChannel(ch <-chan os.Signal) Option {
+	return func(c *Closer) {
+		c.sigCh = ch
+	}
+}
+
+// WithExitFunc sets the func called on forced exit instead of os.Exit.
+func WithExitFunc(exit func(code int)) Option {
+	return func(c *Closer) {
+		c.exit = exit
+	}
+}
+
+// WithTimeout sets the overall shutdown timeout. When it expires CloseAll stops waiting
+// for the remaining closer functions, reports them as timed out and lets Wait return.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Closer) {
+		c.timeout = d
+	}
+}
+
+// WithFuncTimeout sets the timeout applied to every single closer function.
+func WithFuncTimeout(d time.Duration) Option {
+	return func(c *Closer) {
+		c.funcTimeout = d
+	}
+}
+
+// WithExitCode sets the code the process exits with when a signal is received while
+// shutdown is already in progress. Default is 1.
+func WithExitCode(code int) Option {
+	return func(c *Closer) {
+		c.exitCode = code
+	}
+}
+
+// WithDrainDelay sets the delay between pre-shutdown hooks and closing of resources,
+// e.g. to let the load balancer stop routing traffic to the instance which is not ready anymore.
+func WithDrainDelay(d time.Duration) Option {
+	return func(c *Closer) {
+		c.drainDelay = d
+	}
+}
+
+// WithLogger sets the logger used to report closer results. slog.Default() is used by default.
+func WithLogger(log *slog.Logger) Option {
+	return func(c *Closer) {
+		c.log = log
+	}
+}
+
+// entry is a named closer function.
+type entry struct {
+	name    string
+	fn      func(ctx context.Context) error
+	deps    []string
+	timeout time.Duration
+}
+
+// phase is a named group of functions which are executed concurrently.
+type phase struct {
+	name    string
+	entries []entry
 }
 
 // Closer type is used to gather all functions which must be executed when program is finished.
+//
+// Shutdown starts with pre-shutdown hooks (e.g. flipping readiness to "not ready"), then waits
+// for the drain delay and only then closes resources. Functions are grouped into phases.
+// Phases are executed strictly one after another in the order they were first registered,
+// functions inside a phase are executed concurrently unless they are resources depending
+// on each other. Functions added with Add belong to the default phase which is executed
+// after all named phases.
 type Closer struct {
-	mu    sync.Mutex
-	once  sync.Once
-	done  chan struct{}
-	funcs []func() error
+	mu     sync.Mutex
+	once   sync.Once
+	done   chan struct{}
+	phases []*phase
+	def    *phase
+	pre    *phase
+	comp   *phase
+	rep    ShutdownReport
+
+	shuttingDown atomic.Bool
+	graph        map[string][]string
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	pending map[string]int
+
+	log         *slog.Logger
+	signals     []os.Signal
+	sigCh       <-chan os.Signal
+	timeout     time.Duration
+	funcTimeout time.Duration
+	drainDelay  time.Duration
+	exitCode    int
+	exit        func(code int)
 }
 
 // New returns new Closer, if []os.Signal is specified Closer will automatically call CloseAll
-// when one of signals is received from OS
+// when one of signals is received from OS. A signal received while shutdown is in progress
+// forces the process to exit.
 func New(sig ...os.Signal) *Closer {
-	c := &Closer{done: make(chan struct{})}
-	if len(sig) > 0 {
+	return NewWithOptions(WithSignals(sig...))
+}
+
+// NewWithOptions returns new Closer configured with options.
+func NewWithOptions(opts ...Option) *Closer {
+	c := &Closer{
+		done:     make(chan struct{}),
+		def:      &phase{name: DefaultPhase},
+		pre:      &phase{name: PreShutdownPhase},
+		comp:     &phase{name: ComponentPhase},
+		log:      slog.Default(),
+		exitCode: 1,
+		exit:     os.Exit,
+	}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.sigCh == nil && len(c.signals) > 0 {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, c.signals...)
+		c.sigCh = ch
 		go func() {
-			ch := make(chan os.Signal, 1)
-			signal.Notify(ch, sig...)
-			<-ch
+			<-c.done
 			signal.Stop(ch)
-			c.CloseAll()
 		}()
 	}
+	if c.sigCh != nil {
+		go c.handleSignals()
+	}
 
 	return c
 }
 
-// Add func to closer.
-func (c *Closer) Add(f ...func() error) {
+// handleSignals starts shutdown on the first signal and forces exit on the next one.
+func (c *Closer) handleSignals() {
+	select {
+	case sig := <-c.sigCh:
+		c.log.Info("shutdown signal received", slog.String("signal", sig.String()))
+		go c.CloseAll()
+	case <-c.ctx.Done():
+	}
+
+	select {
+	case sig := <-c.sigCh:
+		c.forceExit(sig)
+	case <-c.done:
+	}
+}
+
+// forceExit logs closers which are still pending and terminates the process.
+func (c *Closer) forceExit(sig os.Signal) {
 	c.mu.Lock()
-	c.funcs = append(c.funcs, f...)
+	pending := make([]string, 0, len(c.pending))
+	for name := range c.pending {
+		pending = append(pending, name)
+	}
 	c.mu.Unlock()
+	slices.Sort(pending)
+
+	c.log.Error("forced exit during shutdown",
+		slog.String("signal", sig.String()),
+		slog.Any("pending", pending),
+		slog.Int("exit_code", c.exitCode),
+	)
+	c.exit(c.exitCode)
+}
+
+// IsShuttingDown reports whether shutdown has started. It is meant to be read by health endpoints.
+func (c *Closer) IsShuttingDown() bool {
+	return c.shuttingDown.Load()
+}
+
+// Context returns context which is cancelled when shutdown starts, so background workers
+// and long-running queries can stop.
+func (c *Closer) Context() context.Context {
+	return c.ctx
+}
+
+// Add func to the default phase of closer.
+func (c *Closer) Add(f ...func() error) {
+	c.AddPhase(DefaultPhase, f...)
 }
 
-// Wait blocks until all closer functions are done.
+// AddContext adds context-aware func to the default phase of closer. The context is cancelled
+// when the function timeout or the overall shutdown timeout expires.
+func (c *Closer) AddContext(f ...func(ctx context.Context) error) {
+	c.AddPhaseContext(DefaultPhase, f...)
+}
+
+// AddNamed adds func to the default phase of closer under the given name.
+// The name is used in logs and in errors returned by CloseAllErr and WaitErr.
+func (c *Closer) AddNamed(name string, f ...func() error) {
+	c.add(DefaultPhase, name, withContext(f)...)
+}
+
+// AddNamedContext adds context-aware func to the default phase of closer under the given name.
+func (c *Closer) AddNamedContext(name string, f ...func(ctx context.Context) error) {
+	c.add(DefaultPhase, name, f...)
+}
+
+// AddPhase adds func to the named phase of closer. The phase is created on first use,
+// e.g. "stop accepting", "drain", "close storage" registered in that order are executed in that order.
+func (c *Closer) AddPhase(name string, f ...func() error) {
+	c.add(name, "", withContext(f)...)
+}
+
+// AddPhaseContext adds context-aware func to the named phase of closer.
+func (c *Closer) AddPhaseContext(name string, f ...func(ctx context.Context) error) {
+	c.add(name, "", f...)
+}
+
+// AddPreShutdown adds hooks which are called concurrently as soon as shutdown starts,
+// before the drain delay and before any resource is closed.
+func (c *Closer) AddPreShutdown(f ...func(ctx context.Context) error) {
+	c.add(PreShutdownPhase, "", f...)
+}
+
+// add registers funcs in the phase. Unnamed funcs are named after the phase and their position in it.
+func (c *Closer) add(phaseName, name string, f ...func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p := c.phase(phaseName)
+	for _, fn := range f {
+		n := name
+		if n == "" {
+			n = fmt.Sprintf("%s#%d", p.name, len(p.entries))
+		}
+		p.entries = append(p.entries, entry{name: n, fn: fn})
+	}
+}
+
+// withContext adapts `func() error` callbacks to context-aware ones.
+func withContext(f []func() error) []func(ctx context.Context) error {
+	funcs := make([]func(ctx context.Context) error, 0, len(f))
+	for _, fn := range f {
+		funcs = append(funcs, func(context.Context) error {
+			return fn()
+		})
+	}
+
+	return funcs
+}
+
+// phase returns the phase with the given name creating it if needed. Must be called with mu held.
+func (c *Closer) phase(name string) *phase {
+	switch name {
+	case DefaultPhase:
+		return c.def
+	case PreShutdownPhase:
+		return c.pre
+	case ComponentPhase:
+		return c.comp
+	}
+	for _, p := range c.phases {
+		if p.name == name {
+			return p
+		}
+	}
+	p := &phase{name: name}
+	c.phases = append(c.phases, p)
+
+	return p
+}
+
+// Wait blocks until all closer functions are done or the shutdown timeout expires.
 func (c *Closer) Wait() {
 	<-c.done
 }
 
-// CloseAll calls all closer functions.
+// WaitErr blocks until all closer functions are done and returns errors.Join of all failures.
+func (c *Closer) WaitErr() error {
+	c.Wait()
+
+	return c.rep.Err()
+}
+
+// Report blocks until all closer functions are done and returns the shutdown report.
+func (c *Closer) Report() ShutdownReport {
+	c.Wait()
+
+	return c.rep
+}
+
+// CloseAllErr calls all closer functions and returns errors.Join of all failures.
+func (c *Closer) CloseAllErr() error {
+	c.CloseAll()
+
+	return c.WaitErr()
+}
+
+// CloseAll calls all closer functions phase by phase.
 func (c *Closer) CloseAll() {
 	c.once.Do(func() {
 		defer close(c.done)
+		c.shuttingDown.Store(true)
+		c.cancel()
 
 		c.mu.Lock()
-		funcs := c.funcs
-		c.funcs = nil
+		pre := c.pre
+		phases := append(append([]*phase{c.comp}, c.phases...), c.def)
+		c.phases = nil
+		c.def = &phase{name: DefaultPhase}
+		c.pre = &phase{name: PreShutdownPhase}
+		c.comp = &phase{name: ComponentPhase}
+		c.pending = make(map[string]int)
+		for _, p := range append([]*phase{pre}, phases...) {
+			for _, e := range p.entries {
+				c.pending[e.name]++
+			}
+		}
 		c.mu.Unlock()
 
-		// call all Closer funcs async
-		errs := make(chan error, len(funcs))
-		for _, f := range funcs {
-			go func(f func() error) {
-				errs <- f()
-			}(f)
+		ctx := context.Background()
+		if c.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, c.timeout)
+			defer cancel()
 		}
 
-		for i := 0; i < cap(errs); i++ {
-			if err := <-errs; err != nil {
-				log.Println("error returned from Closer")
+		c.rep.Start = time.Now()
+		c.runPhase(ctx, pre)
+		c.drain(ctx)
+
+		for _, p := range phases {
+			if ctx.Err() != nil {
+				now := time.Now()
+				for _, e := range p.entries {
+					c.report(p, e, now, now, ErrTimeout)
+				}
+				continue
 			}
+			c.runPhase(ctx, p)
 		}
+		c.rep.End = time.Now()
+		c.rep.Duration = c.rep.End.Sub(c.rep.Start)
+
+		c.log.Info("shutdown finished", slog.Any("report", c.rep))
 	})
 }
+
+// drain waits for the drain delay or until the context is cancelled.
+func (c *Closer) drain(ctx context.Context) {
+	if c.drainDelay <= 0 {
+		return
+	}
+
+	c.log.Info("draining before closing resources", slog.Duration("delay", c.drainDelay))
+	t := time.NewTimer(c.drainDelay)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
+
+// runPhase calls all functions of the phase async and waits until they are done
+// or the context is cancelled. A function is called only after all functions which
+// depend on it are done, so resources are closed in reverse-topological order.
+func (c *Closer) runPhase(ctx context.Context, p *phase) {
+	type result struct {
+		idx        int
+		start, end time.Time
+		err        error
+	}
+
+	dependents := p.dependents()
+	done := make([]chan struct{}, len(p.entries))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	results := make(chan result, len(p.entries))
+	for i, e := range p.entries {
+		go func(i int, e entry) {
+			defer close(done[i])
+
+			for _, j := range dependents[i] {
+				select {
+				case <-done[j]:
+				case <-ctx.Done():
+					now := time.Now()
+					results <- result{idx: i, start: now, end: now, err: ErrTimeout}
+					return
+				}
+			}
+
+			start := time.Now()
+			err := c.call(ctx, e)
+			results <- result{idx: i, start: start, end: time.Now(), err: err}
+		}(i, e)
+	}
+
+	start := time.Now()
+	finished := make([]bool, len(p.entries))
+	collect := func(r result) {
+		finished[r.idx] = true
+		c.report(p, p.entries[r.idx], r.start, r.end, r.err)
+	}
+	for range p.entries {
+		select {
+		case r := <-results:
+			collect(r)
+		case <-ctx.Done():
+			// Select may pick the deadline while results of closers finished in time are still buffered.
+			for len(results) > 0 {
+				collect(<-results)
+			}
+
+			now := time.Now()
+			for i, ok := range finished {
+				if !ok {
+					c.report(p, p.entries[i], start, now, ErrTimeout)
+				}
+			}
+			return
+		}
+	}
+}
+
+// report logs the closer result and adds it to the shutdown report.
+func (c *Closer) report(p *phase, e entry, start, end time.Time, err error) {
+	c.mu.Lock()
+	if c.pending[e.name]--; c.pending[e.name] <= 0 {
+		delete(c.pending, e.name)
+	}
+	c.mu.Unlock()
+
+	r := CloserReport{
+		Name:     e.name,
+		Phase:    p.name,
+		Start:    start,
+		End:      end,
+		Duration: end.Sub(start),
+		TimedOut: errors.Is(err, ErrTimeout),
+	}
+	if err != nil {
+		r.Err = fmt.Errorf("%s: %w", e.name, err)
+	}
+	c.rep.Closers = append(c.rep.Closers, r)
+
+	attrs := []any{
+		slog.String("closer", r.Name),
+		slog.String("phase", r.Phase),
+		slog.Duration("duration", r.Duration),
+	}
+	if err == nil {
+		c.log.Debug("closer finished", attrs...)
+		return
+	}
+
+	msg := "error returned from closer"
+	if r.TimedOut {
+		msg = "closer timed out"
+	}
+	attrs = append(attrs, sl.Err(err))
+
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		msg = "closer panicked"
+		attrs = append(attrs, slog.String("stack", string(panicErr.Stack)))
+	}
+	c.log.Error(msg, attrs...)
+}
+
+// call runs the entry applying the function timeout. If it does not return in time ErrTimeout
+// is returned and it is left running in background. A panic is converted into PanicError.
+func (c *Closer) call(ctx context.Context, e entry) error {
+	timeout := c.funcTimeout
+	if e.timeout > 0 {
+		timeout = e.timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errCh <- &PanicError{Value: r, Stack: debug.Stack()}
+			}
+		}()
+		errCh <- e.fn(ctx)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ErrTimeout
+	}
+}
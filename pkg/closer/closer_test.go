@@ -0,0 +1,387 @@
+package closer_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/microservice-common/pkg/closer"
+)
+
+// recorder collects names of called closers in the order they were called.
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) add(name string) func() error {
+	return func() error {
+		r.mu.Lock()
+		r.calls = append(r.calls, name)
+		r.mu.Unlock()
+
+		return nil
+	}
+}
+
+func (r *recorder) addContext(name string) func(ctx context.Context) error {
+	f := r.add(name)
+
+	return func(context.Context) error {
+		return f()
+	}
+}
+
+func (r *recorder) called() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.calls)
+}
+
+func newCloser(opts ...closer.Option) *closer.Closer {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	return closer.NewWithOptions(append([]closer.Option{closer.WithLogger(log)}, opts...)...)
+}
+
+func TestPhaseOrder(t *testing.T) {
+	var rec recorder
+	c := newCloser()
+
+	c.Add(rec.add("default"))
+	c.AddPhase("stop accepting", rec.add("stop accepting"))
+	c.AddPhase("close storage", rec.add("close storage"))
+	c.AddPhase("stop accepting", rec.add("stop accepting"))
+
+	c.CloseAll()
+	c.Wait()
+
+	want := []string{"stop accepting", "stop accepting", "close storage", "default"}
+	if got := rec.called(); !slices.Equal(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestCloseAllOnce(t *testing.T) {
+	var rec recorder
+	c := newCloser()
+	c.Add(rec.add("a"))
+
+	c.CloseAll()
+	c.CloseAll()
+
+	if got := rec.called(); len(got) != 1 {
+		t.Errorf("calls = %v, want a single call", got)
+	}
+}
+
+// block returns closer func which blocks until the test is finished.
+func block(t *testing.T) func() error {
+	t.Helper()
+
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	return func() error {
+		<-release
+
+		return nil
+	}
+}
+
+func closerReport(t *testing.T, rep closer.ShutdownReport, name string) closer.CloserReport {
+	t.Helper()
+
+	for _, c := range rep.Closers {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("closer %q is not reported", name)
+
+	return closer.CloserReport{}
+}
+
+func TestContextFuncs(t *testing.T) {
+	var got context.Context
+	c := newCloser()
+	c.AddContext(func(ctx context.Context) error {
+		got = ctx
+
+		return nil
+	})
+
+	if err := c.CloseAllErr(); err != nil {
+		t.Fatalf("CloseAllErr: %v", err)
+	}
+	if got == nil {
+		t.Error("context-aware closer is not called")
+	}
+}
+
+func TestFuncTimeout(t *testing.T) {
+	var rec recorder
+	c := newCloser(closer.WithFuncTimeout(10 * time.Millisecond))
+
+	c.AddNamed("slow", block(t))
+	c.AddNamed("fast", rec.add("fast"))
+
+	if err := c.CloseAllErr(); !errors.Is(err, closer.ErrTimeout) {
+		t.Errorf("error = %v, want %v", err, closer.ErrTimeout)
+	}
+
+	rep := c.Report()
+	if !closerReport(t, rep, "slow").TimedOut {
+		t.Error("slow closer is not reported as timed out")
+	}
+	if r := closerReport(t, rep, "fast"); r.Err != nil {
+		t.Errorf("fast closer error = %v", r.Err)
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	var rec recorder
+	c := newCloser(closer.WithTimeout(50 * time.Millisecond))
+
+	c.AddPhase("first", rec.add("fast"), block(t))
+	c.AddPhase("second", rec.add("second"))
+
+	done := make(chan struct{})
+	go func() {
+		c.CloseAll()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("CloseAll did not return after the shutdown timeout")
+	}
+
+	if got := rec.called(); !slices.Equal(got, []string{"fast"}) {
+		t.Errorf("calls = %v, phases after the timeout must not be called", got)
+	}
+
+	rep := c.Report()
+	// The result of the closer finished in time must not be lost when the deadline fires.
+	if r := closerReport(t, rep, "first#0"); r.Err != nil {
+		t.Errorf("closer finished in time reported with error %v", r.Err)
+	}
+	for _, name := range []string{"first#1", "second#0"} {
+		if !closerReport(t, rep, name).TimedOut {
+			t.Errorf("closer %s is not reported as timed out", name)
+		}
+	}
+}
+
+func TestJoinedErrors(t *testing.T) {
+	errPG := errors.New("pg failed")
+	errRedis := errors.New("redis failed")
+	c := newCloser()
+
+	c.AddNamed("pg", func() error { return errPG })
+	c.AddNamedContext("redis", func(context.Context) error { return errRedis })
+	c.AddNamed("kafka", func() error { return nil })
+
+	err := c.CloseAllErr()
+	if !errors.Is(err, errPG) || !errors.Is(err, errRedis) {
+		t.Fatalf("error = %v, want both failures joined", err)
+	}
+	for _, name := range []string{"pg: ", "redis: "} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q does not name the closer %q", err, name)
+		}
+	}
+	if err := c.WaitErr(); err == nil {
+		t.Error("WaitErr = nil after failed shutdown")
+	}
+}
+
+func TestPanicRecovery(t *testing.T) {
+	var rec recorder
+	c := newCloser()
+
+	c.AddNamed("broken", func() error { panic("boom") })
+	c.AddNamed("redis", rec.add("redis"))
+
+	err := c.CloseAllErr()
+
+	var panicErr *closer.PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("error = %v, want PanicError", err)
+	}
+	if panicErr.Value != "boom" || len(panicErr.Stack) == 0 {
+		t.Errorf("PanicError = %v with %d bytes of stack", panicErr.Value, len(panicErr.Stack))
+	}
+	if got := rec.called(); !slices.Equal(got, []string{"redis"}) {
+		t.Errorf("calls = %v, closers after a panic must still be called", got)
+	}
+}
+
+func TestShutdownContext(t *testing.T) {
+	c := newCloser()
+
+	var ctxErr error
+	c.Add(func() error {
+		ctxErr = c.Context().Err()
+
+		return nil
+	})
+
+	if err := c.Context().Err(); err != nil {
+		t.Fatalf("Context is cancelled before shutdown: %v", err)
+	}
+	c.CloseAll()
+
+	if !errors.Is(ctxErr, context.Canceled) {
+		t.Errorf("Context error in closer = %v, want %v", ctxErr, context.Canceled)
+	}
+}
+
+func TestForcedExit(t *testing.T) {
+	sig := make(chan os.Signal)
+	exited := make(chan int, 1)
+	c := newCloser(
+		closer.WithSignalChannel(sig),
+		closer.WithExitCode(3),
+		closer.WithExitFunc(func(code int) { exited <- code }),
+	)
+	c.AddNamed("slow", block(t))
+
+	sig <- syscall.SIGTERM
+	select {
+	case <-c.Context().Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown is not started by the first signal")
+	}
+
+	sig <- syscall.SIGINT
+	select {
+	case code := <-exited:
+		if code != 3 {
+			t.Errorf("exit code = %d, want 3", code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("exit is not forced by the second signal")
+	}
+}
+
+func TestPreShutdown(t *testing.T) {
+	var rec recorder
+	c := newCloser()
+
+	var shuttingDown bool
+	c.AddPhase("stop accepting", rec.add("stop accepting"))
+	c.AddPreShutdown(func(ctx context.Context) error {
+		shuttingDown = c.IsShuttingDown()
+
+		return rec.addContext("pre-shutdown")(ctx)
+	})
+
+	if c.IsShuttingDown() {
+		t.Fatal("IsShuttingDown = true before shutdown")
+	}
+	c.CloseAll()
+
+	if !shuttingDown {
+		t.Error("IsShuttingDown = false in pre-shutdown hook")
+	}
+	if got := rec.called(); !slices.Equal(got, []string{"pre-shutdown", "stop accepting"}) {
+		t.Errorf("calls = %v, pre-shutdown hooks must be called first", got)
+	}
+}
+
+func TestDrainDelay(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		preAt   time.Time
+		closeAt time.Time
+	)
+	c := newCloser(closer.WithDrainDelay(50 * time.Millisecond))
+	c.AddPreShutdown(func(context.Context) error {
+		mu.Lock()
+		preAt = time.Now()
+		mu.Unlock()
+
+		return nil
+	})
+	c.Add(func() error {
+		mu.Lock()
+		closeAt = time.Now()
+		mu.Unlock()
+
+		return nil
+	})
+
+	c.CloseAll()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if d := closeAt.Sub(preAt); d < 50*time.Millisecond {
+		t.Errorf("resources closed %v after pre-shutdown hooks, want at least the drain delay", d)
+	}
+}
+
+func TestSignalChannel(t *testing.T) {
+	var rec recorder
+	sig := make(chan os.Signal, 1)
+	c := newCloser(closer.WithSignalChannel(sig))
+	c.Add(rec.add("a"))
+
+	sig <- syscall.SIGTERM
+
+	done := make(chan struct{})
+	go func() {
+		c.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown is not triggered by the signal channel")
+	}
+	if got := rec.called(); !slices.Equal(got, []string{"a"}) {
+		t.Errorf("calls = %v, want [a]", got)
+	}
+}
+
+func TestSetGlobal(t *testing.T) {
+	prev := closer.Global()
+	c := newCloser()
+	restore := closer.SetGlobal(c)
+
+	var rec recorder
+	closer.Add(rec.add("a"))
+	closer.AddPhase("first", rec.add("first"))
+	if err := closer.CloseAllErr(); err != nil {
+		t.Fatalf("CloseAllErr: %v", err)
+	}
+	closer.Wait()
+
+	if !slices.Equal(rec.called(), []string{"first", "a"}) {
+		t.Errorf("calls = %v, want [first a]", rec.called())
+	}
+	if !closer.IsShuttingDown() {
+		t.Error("IsShuttingDown = false for the swapped global closer")
+	}
+
+	restore()
+	if closer.Global() != prev {
+		t.Error("restore did not bring back the previous global closer")
+	}
+
+	closer.ResetGlobal()
+	t.Cleanup(func() { closer.SetGlobal(prev) })
+	if closer.Global() == prev || closer.IsShuttingDown() {
+		t.Error("ResetGlobal did not install a fresh global closer")
+	}
+}
@@ -0,0 +1,164 @@
+package closer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+
+	"github.com/8thgencore/microservice-common/pkg/logger/sl"
+)
+
+// Component is a part of application which is started and stopped by Runner.
+// Start must not block: long-running work should be launched with Runner.Go.
+type Component interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+// Hooks adapts start and stop functions to Component. Any of them may be nil.
+type Hooks struct {
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Start implements Component interface.
+func (h Hooks) Start(ctx context.Context) error {
+	if h.OnStart == nil {
+		return nil
+	}
+
+	return h.OnStart(ctx)
+}
+
+// Stop implements Component interface.
+func (h Hooks) Stop(ctx context.Context) error {
+	if h.OnStop == nil {
+		return nil
+	}
+
+	return h.OnStop(ctx)
+}
+
+// component is a named Component registered in Runner.
+type component struct {
+	name string
+	comp Component
+}
+
+// Runner starts application components in order and stops them through Closer in ComponentPhase,
+// which is executed before the named and the default phases, so servers are stopped before storages
+// registered in Closer directly. Any start failure or runtime error triggers the same shutdown path.
+type Runner struct {
+	closer *Closer
+
+	mu         sync.Mutex
+	components []component
+
+	failOnce sync.Once
+	failed   chan struct{}
+	failErr  error
+}
+
+// NewRunner creates Runner which stops components using the given Closer.
+func NewRunner(c *Closer) *Runner {
+	return &Runner{
+		closer: c,
+		failed: make(chan struct{}),
+	}
+}
+
+// Add registers component. Components are started in the order they were added
+// and stopped in reverse order.
+func (r *Runner) Add(name string, comp Component) {
+	r.mu.Lock()
+	r.components = append(r.components, component{name: name, comp: comp})
+	r.mu.Unlock()
+}
+
+// Go runs fn in background with context which is cancelled when shutdown starts.
+// A non-nil error returned before shutdown triggers shutdown and is returned from Run.
+func (r *Runner) Go(name string, fn func(ctx context.Context) error) {
+	ctx := r.closer.Context()
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				r.Fail(fmt.Errorf("%s: %w", name, &PanicError{Value: v, Stack: debug.Stack()}))
+			}
+		}()
+
+		if err := fn(ctx); err != nil && ctx.Err() == nil {
+			r.Fail(fmt.Errorf("%s: %w", name, err))
+		}
+	}()
+}
+
+// Fail reports a runtime error and triggers shutdown. Only the first error is kept.
+func (r *Runner) Fail(err error) {
+	r.failOnce.Do(func() {
+		r.failErr = err
+		close(r.failed)
+	})
+}
+
+// Run starts all components and blocks until ctx is done, shutdown is started by Closer
+// (e.g. by a signal) or a component fails. Then all started components are stopped and
+// the failure is returned joined with errors of the shutdown.
+func (r *Runner) Run(ctx context.Context) error {
+	startCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(r.closer.Context(), cancel)
+	defer stop()
+
+	r.mu.Lock()
+	components := r.components
+	r.mu.Unlock()
+
+	if err := r.start(startCtx, components); err != nil {
+		r.Fail(err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-r.closer.Context().Done():
+	case <-r.failed:
+		r.closer.log.Error("component failed, shutting down", sl.Err(r.failErr))
+	}
+
+	err := r.closer.CloseAllErr()
+	// Errors reported after shutdown are caused by it and are ignored.
+	r.failOnce.Do(func() {})
+
+	return errors.Join(r.failErr, err)
+}
+
+// start starts components in order registering their Stop in Closer as soon as they are started.
+func (r *Runner) start(ctx context.Context, components []component) error {
+	var prev []string
+	for _, c := range components {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		r.closer.log.Debug("starting component", slog.String("component", c.name))
+		if err := c.comp.Start(ctx); err != nil {
+			return fmt.Errorf("start %s: %w", c.name, err)
+		}
+
+		// Depending on the previously started component makes Closer stop components in reverse order.
+		err := r.closer.AddResource(Resource{
+			Name:      c.name,
+			Phase:     ComponentPhase,
+			DependsOn: prev,
+			Close:     c.comp.Stop,
+		})
+		if err != nil {
+			return errors.Join(err, c.comp.Stop(ctx))
+		}
+		prev = []string{c.name}
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package closer_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/8thgencore/microservice-common/pkg/closer"
+)
+
+func TestRunnerStopsComponentsFirst(t *testing.T) {
+	var rec recorder
+	c := newCloser()
+	c.AddPhase("close storage", rec.add("pg"))
+	c.Add(rec.add("logger"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	r := closer.NewRunner(c)
+	r.Add("grpc", closer.Hooks{OnStop: rec.addContext("grpc")})
+	r.Add("http", closer.Hooks{
+		OnStart: func(context.Context) error {
+			cancel()
+
+			return nil
+		},
+		OnStop: rec.addContext("http"),
+	})
+
+	if err := r.Run(ctx); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	want := []string{"http", "grpc", "pg", "logger"}
+	if got := rec.called(); !slices.Equal(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestRunnerRollback(t *testing.T) {
+	errStart := errors.New("start failed")
+
+	var rec recorder
+	c := newCloser()
+	r := closer.NewRunner(c)
+	r.Add("db", closer.Hooks{
+		OnStart: func(context.Context) error { return rec.add("start db")() },
+		OnStop:  rec.addContext("stop db"),
+	})
+	r.Add("cache", closer.Hooks{
+		OnStart: func(context.Context) error { return errStart },
+		OnStop:  rec.addContext("stop cache"),
+	})
+	r.Add("server", closer.Hooks{
+		OnStart: func(context.Context) error { return rec.add("start server")() },
+		OnStop:  rec.addContext("stop server"),
+	})
+
+	if err := r.Run(context.Background()); !errors.Is(err, errStart) {
+		t.Fatalf("Run error = %v, want %v", err, errStart)
+	}
+
+	want := []string{"start db", "stop db"}
+	if got := rec.called(); !slices.Equal(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestRunnerGo(t *testing.T) {
+	errWorker := errors.New("worker failed")
+
+	tests := []struct {
+		name    string
+		fn      func(ctx context.Context) error
+		wantErr func(err error) bool
+	}{
+		{
+			name:    "error",
+			fn:      func(context.Context) error { return errWorker },
+			wantErr: func(err error) bool { return errors.Is(err, errWorker) },
+		},
+		{
+			name: "panic",
+			fn:   func(context.Context) error { panic("boom") },
+			wantErr: func(err error) bool {
+				var panicErr *closer.PanicError
+
+				return errors.As(err, &panicErr)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rec recorder
+			c := newCloser()
+			r := closer.NewRunner(c)
+			r.Add("server", closer.Hooks{
+				OnStart: func(context.Context) error {
+					r.Go("worker", tt.fn)
+
+					return nil
+				},
+				OnStop: rec.addContext("stop server"),
+			})
+
+			if err := r.Run(context.Background()); !tt.wantErr(err) {
+				t.Fatalf("Run error = %v", err)
+			}
+			if got := rec.called(); !slices.Equal(got, []string{"stop server"}) {
+				t.Errorf("calls = %v, the started component must be stopped", got)
+			}
+		})
+	}
+}
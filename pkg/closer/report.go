@@ -0,0 +1,90 @@
+package closer
+
+import (
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// CloserReport describes how a single closer finished.
+type CloserReport struct {
+	Name     string
+	Phase    string
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
+	Err      error
+	TimedOut bool
+}
+
+// LogValue implements slog.LogValuer interface.
+func (r CloserReport) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("name", r.Name),
+		slog.String("phase", r.Phase),
+		slog.Duration("duration", r.Duration),
+	}
+	if r.TimedOut {
+		attrs = append(attrs, slog.Bool("timed_out", true))
+	}
+	if r.Err != nil {
+		attrs = append(attrs, slog.String("error", r.Err.Error()))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// ShutdownReport describes the whole shutdown. Closers are listed in the order they finished.
+type ShutdownReport struct {
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
+	Closers  []CloserReport
+}
+
+// Err returns errors.Join of all closer failures.
+func (r ShutdownReport) Err() error {
+	errs := make([]error, 0, len(r.Closers))
+	for _, c := range r.Closers {
+		errs = append(errs, c.Err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Slowest returns the report of the closer which took the longest time.
+func (r ShutdownReport) Slowest() (CloserReport, bool) {
+	if len(r.Closers) == 0 {
+		return CloserReport{}, false
+	}
+
+	slowest := r.Closers[0]
+	for _, c := range r.Closers[1:] {
+		if c.Duration > slowest.Duration {
+			slowest = c
+		}
+	}
+
+	return slowest, true
+}
+
+// LogValue implements slog.LogValuer interface.
+func (r ShutdownReport) LogValue() slog.Value {
+	failed := 0
+	closers := make([]slog.Attr, 0, len(r.Closers))
+	for i, c := range r.Closers {
+		if c.Err != nil {
+			failed++
+		}
+		// Names are not unique, e.g. AddNamed with several funcs, so closers are keyed by position.
+		closers = append(closers, slog.Any(strconv.Itoa(i), c))
+	}
+
+	return slog.GroupValue(
+		slog.Duration("duration", r.Duration),
+		slog.Int("total", len(r.Closers)),
+		slog.Int("failed", failed),
+		slog.Attr{Key: "closers", Value: slog.GroupValue(closers...)},
+	)
+}
@@ -0,0 +1,40 @@
+package closer_test
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/microservice-common/pkg/closer"
+)
+
+func TestReportLogValue(t *testing.T) {
+	c := newCloser()
+	c.AddNamed("pg", func() error { return nil }, func() error { return nil })
+	c.CloseAll()
+
+	var closers []slog.Attr
+	for _, a := range c.Report().LogValue().Group() {
+		if a.Key == "closers" {
+			closers = a.Value.Group()
+		}
+	}
+	if len(closers) != 2 || closers[0].Key == closers[1].Key {
+		t.Errorf("closers = %v, want two entries with distinct keys", closers)
+	}
+}
+
+func TestReportSlowest(t *testing.T) {
+	rep := closer.ShutdownReport{Closers: []closer.CloserReport{
+		{Name: "a", Duration: time.Second},
+		{Name: "b", Duration: 3 * time.Second},
+		{Name: "c", Duration: 2 * time.Second},
+	}}
+
+	if got, ok := rep.Slowest(); !ok || got.Name != "b" {
+		t.Errorf("Slowest = %v, %v, want b", got.Name, ok)
+	}
+	if _, ok := (closer.ShutdownReport{}).Slowest(); ok {
+		t.Error("Slowest of empty report is reported as found")
+	}
+}
@@ -0,0 +1,127 @@
+package closer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidResource is returned when a resource has no name or no close func.
+	ErrInvalidResource = errors.New("closer: resource name and close func are required")
+	// ErrDuplicateResource is returned when a resource with the same name is already registered.
+	ErrDuplicateResource = errors.New("closer: resource already registered")
+	// ErrDependencyCycle is returned when registering a resource would create a dependency cycle.
+	ErrDependencyCycle = errors.New("closer: dependency cycle")
+)
+
+// Resource describes a named closer which depends on other named closers.
+type Resource struct {
+	// Name identifies the resource in logs, errors and DependsOn of other resources.
+	Name string
+	// Phase is the phase the resource is closed in, DefaultPhase if empty.
+	Phase string
+	// DependsOn lists names of closers used by the resource. The resource is closed before
+	// all of them. Dependencies on closers of other phases are ordered by phases only.
+	DependsOn []string
+	// Timeout overrides the per-func timeout for the resource.
+	Timeout time.Duration
+	// Close releases the resource.
+	Close func(ctx context.Context) error
+}
+
+// AddResource adds resource to the globalCloser.
+func AddResource(r Resource) error {
+	return Global().AddResource(r)
+}
+
+// AddResource adds resource to closer. Resources are torn down in reverse-topological order:
+// a resource is closed only after all closers depending on it are done, independent branches
+// are closed concurrently. Dependencies may reference resources which are registered later.
+func (c *Closer) AddResource(r Resource) error {
+	if r.Name == "" || r.Close == nil {
+		return ErrInvalidResource
+	}
+	if r.Phase == "" {
+		r.Phase = DefaultPhase
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.graph == nil {
+		c.graph = make(map[string][]string)
+	}
+	if _, ok := c.graph[r.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateResource, r.Name)
+	}
+	for _, dep := range r.DependsOn {
+		if path := c.path(dep, r.Name); path != nil {
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(append([]string{r.Name}, path...), " -> "))
+		}
+	}
+	c.graph[r.Name] = append([]string{}, r.DependsOn...)
+
+	p := c.phase(r.Phase)
+	p.entries = append(p.entries, entry{
+		name:    r.Name,
+		fn:      r.Close,
+		deps:    c.graph[r.Name],
+		timeout: r.Timeout,
+	})
+
+	return nil
+}
+
+// path returns the dependency path from one resource to another or nil if there is none.
+// Must be called with mu held.
+func (c *Closer) path(from, to string) []string {
+	if from == to {
+		return []string{from}
+	}
+
+	visited := make(map[string]bool)
+	var walk func(name string) []string
+	walk = func(name string) []string {
+		if name == to {
+			return []string{name}
+		}
+		if visited[name] {
+			return nil
+		}
+		visited[name] = true
+
+		for _, dep := range c.graph[name] {
+			if p := walk(dep); p != nil {
+				return append([]string{name}, p...)
+			}
+		}
+
+		return nil
+	}
+
+	return walk(from)
+}
+
+// dependents returns for every entry of the phase indexes of entries depending on it.
+func (p *phase) dependents() [][]int {
+	byName := make(map[string][]int, len(p.entries))
+	for i, e := range p.entries {
+		byName[e.name] = append(byName[e.name], i)
+	}
+
+	dependents := make([][]int, len(p.entries))
+	for i, e := range p.entries {
+		for _, dep := range e.deps {
+			for _, j := range byName[dep] {
+				if j != i {
+					dependents[j] = append(dependents[j], i)
+				}
+			}
+		}
+	}
+
+	return dependents
+}
@@ -0,0 +1,115 @@
+package closer_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/8thgencore/microservice-common/pkg/closer"
+)
+
+func TestDependencies(t *testing.T) {
+	var rec recorder
+	c := newCloser()
+
+	// Dependencies may be registered later than their dependents.
+	resources := []closer.Resource{
+		{Name: "server", DependsOn: []string{"repo", "cache"}, Close: rec.addContext("server")},
+		{Name: "repo", DependsOn: []string{"db"}, Close: rec.addContext("repo")},
+		{Name: "db", Close: rec.addContext("db")},
+		{Name: "cache", Close: rec.addContext("cache")},
+	}
+	for _, r := range resources {
+		if err := c.AddResource(r); err != nil {
+			t.Fatalf("AddResource(%s): %v", r.Name, err)
+		}
+	}
+
+	if err := c.CloseAllErr(); err != nil {
+		t.Fatalf("CloseAllErr: %v", err)
+	}
+
+	got := rec.called()
+	before := func(a, b string) bool {
+		return slices.Index(got, a) < slices.Index(got, b)
+	}
+	if len(got) != 4 || !before("server", "repo") || !before("repo", "db") || !before("server", "cache") {
+		t.Errorf("calls = %v, dependents must be closed before their dependencies", got)
+	}
+}
+
+func TestResourceTimeout(t *testing.T) {
+	c := newCloser(closer.WithFuncTimeout(time.Hour))
+
+	slow := block(t)
+	err := c.AddResource(closer.Resource{
+		Name:    "slow",
+		Timeout: 10 * time.Millisecond,
+		Close:   func(context.Context) error { return slow() },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.CloseAllErr(); !errors.Is(err, closer.ErrTimeout) {
+		t.Errorf("error = %v, want %v", err, closer.ErrTimeout)
+	}
+}
+
+func TestAddResourceErrors(t *testing.T) {
+	noop := func(context.Context) error { return nil }
+
+	tests := []struct {
+		name    string
+		add     []closer.Resource
+		wantErr error
+	}{
+		{
+			name:    "no name",
+			add:     []closer.Resource{{Close: noop}},
+			wantErr: closer.ErrInvalidResource,
+		},
+		{
+			name:    "no close func",
+			add:     []closer.Resource{{Name: "a"}},
+			wantErr: closer.ErrInvalidResource,
+		},
+		{
+			name:    "duplicate",
+			add:     []closer.Resource{{Name: "a", Close: noop}, {Name: "a", Close: noop}},
+			wantErr: closer.ErrDuplicateResource,
+		},
+		{
+			name:    "self dependency",
+			add:     []closer.Resource{{Name: "a", DependsOn: []string{"a"}, Close: noop}},
+			wantErr: closer.ErrDependencyCycle,
+		},
+		{
+			name: "cycle",
+			add: []closer.Resource{
+				{Name: "a", DependsOn: []string{"b"}, Close: noop},
+				{Name: "b", DependsOn: []string{"c"}, Close: noop},
+				{Name: "c", DependsOn: []string{"a"}, Close: noop},
+			},
+			wantErr: closer.ErrDependencyCycle,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newCloser()
+
+			var err error
+			for _, r := range tt.add {
+				if err = c.AddResource(r); err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}